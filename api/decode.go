@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/Unleash/unleash-client-go/v4/strategy"
+)
+
+// InvalidFeatureError reports a feature toggle that was left out of a payload
+// because it could not be decoded or failed validation.
+type InvalidFeatureError struct {
+	// Feature is the name of the feature toggle, if it could be determined.
+	Feature string
+
+	// Path is the JSON path of the invalid value, such as
+	// $.features[3].strategies[0].parameters.stickiness.
+	Path string
+
+	// Err describes the problem.
+	Err error
+}
+
+func (e *InvalidFeatureError) Error() string {
+	return fmt.Sprintf("feature toggle %q is invalid at %s and was ignored: %v", e.Feature, e.Path, e.Err)
+}
+
+func (e *InvalidFeatureError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidSegmentError reports a segment that was left out of a payload because
+// it could not be decoded.
+type InvalidSegmentError struct {
+	// Segment is the id of the segment, if it could be determined.
+	Segment int
+
+	// Path is the JSON path of the invalid value.
+	Path string
+
+	// Err describes the problem.
+	Err error
+}
+
+func (e *InvalidSegmentError) Error() string {
+	return fmt.Sprintf("segment %d is invalid at %s and was ignored: %v", e.Segment, e.Path, e.Err)
+}
+
+func (e *InvalidSegmentError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeFeatureResponse decodes a feature toggles payload, accepting every
+// feature toggle and segment that is valid. Those that are not are left out of
+// the response and reported as InvalidFeatureError and InvalidSegmentError. An
+// error is only returned when the payload as a whole cannot be decoded.
+func DecodeFeatureResponse(r io.Reader) (FeatureResponse, []error, error) {
+	var raw struct {
+		Response
+		Features []json.RawMessage `json:"features"`
+		Segments []json.RawMessage `json:"segments"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return FeatureResponse{}, nil, err
+	}
+
+	resp := FeatureResponse{Response: raw.Response}
+	var invalid []error
+
+	for i, data := range raw.Features {
+		path := fmt.Sprintf("$.features[%d]", i)
+		var f Feature
+		if err := json.Unmarshal(data, &f); err != nil {
+			var named struct {
+				Name string `json:"name"`
+			}
+			_ = json.Unmarshal(data, &named)
+			invalid = append(invalid, &InvalidFeatureError{Feature: named.Name, Path: jsonPath(path, err), Err: err})
+			continue
+		}
+		if subPath, err := f.validate(); err != nil {
+			invalid = append(invalid, &InvalidFeatureError{Feature: f.Name, Path: path + subPath, Err: err})
+			continue
+		}
+		resp.Features = append(resp.Features, f)
+	}
+
+	for i, data := range raw.Segments {
+		path := fmt.Sprintf("$.segments[%d]", i)
+		var s Segment
+		if err := json.Unmarshal(data, &s); err != nil {
+			var id struct {
+				Id int `json:"id"`
+			}
+			_ = json.Unmarshal(data, &id)
+			invalid = append(invalid, &InvalidSegmentError{Segment: id.Id, Path: jsonPath(path, err), Err: err})
+			continue
+		}
+		resp.Segments = append(resp.Segments, s)
+	}
+
+	return resp, invalid, nil
+}
+
+// jsonPath appends the location of a decoding error to the path of the decoded
+// value, when encoding/json reports it.
+func jsonPath(path string, err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return path + "." + typeErr.Field
+	}
+	return path
+}
+
+type parameterKind int
+
+const (
+	stringParameter parameterKind = iota
+	numberParameter
+)
+
+// builtinParameters lists the types of the parameters used by the built-in
+// strategies.
+var builtinParameters = map[string]map[string]parameterKind{
+	"flexibleRollout": {
+		strategy.ParamRollout:    numberParameter,
+		strategy.ParamStickiness: stringParameter,
+		strategy.ParamGroupId:    stringParameter,
+	},
+	"gradualRolloutRandom": {
+		strategy.ParamPercentage: numberParameter,
+	},
+	"gradualRolloutSessionId": {
+		strategy.ParamPercentage: numberParameter,
+		strategy.ParamGroupId:    stringParameter,
+	},
+	"gradualRolloutUserId": {
+		strategy.ParamPercentage: numberParameter,
+		strategy.ParamGroupId:    stringParameter,
+	},
+	"userWithId": {
+		strategy.ParamUserIds: stringParameter,
+	},
+	"remoteAddress": {
+		strategy.ParamIps: stringParameter,
+	},
+	"applicationHostname": {
+		strategy.ParamHostNames: stringParameter,
+	},
+}
+
+// validate checks the parameters of the built-in strategies, which are loosely
+// typed in the payload. It returns the path of the first invalid value relative
+// to the feature toggle.
+func (f Feature) validate() (string, error) {
+	for i, st := range f.Strategies {
+		kinds := builtinParameters[st.Name]
+		names := make([]string, 0, len(kinds))
+		for name := range kinds {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			kind := kinds[name]
+			value, found := st.Parameters[name]
+			if !found || value == nil {
+				continue
+			}
+			path := fmt.Sprintf(".strategies[%d].parameters.%s", i, name)
+			switch kind {
+			case stringParameter:
+				if _, ok := value.(string); !ok {
+					return path, fmt.Errorf("parameter %s of strategy %s must be a string, got %T", name, st.Name, value)
+				}
+			case numberParameter:
+				if !isNumber(value) {
+					return path, fmt.Errorf("parameter %s of strategy %s must be a number, got %v", name, st.Name, value)
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+func isNumber(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		return true
+	case string:
+		if v == "" {
+			return true
+		}
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFeatureResponse(t *testing.T) {
+	assert := assert.New(t)
+
+	payload := `{
+		"version": 2,
+		"features": [
+			{"name": "valid", "enabled": true, "strategies": [{"name": "flexibleRollout", "parameters": {"rollout": "50", "stickiness": "default", "groupId": "valid"}}]},
+			{"name": "bad-stickiness", "enabled": true, "strategies": [{"name": "flexibleRollout", "parameters": {"rollout": 50, "stickiness": 1}}]},
+			{"name": "bad-type", "enabled": "yes"},
+			{"name": "future", "enabled": true, "somethingNew": {"a": 1}, "strategies": [{"name": "default", "constraints": [{"contextName": "userId", "operator": "FUTURE_OP"}]}]}
+		],
+		"segments": [
+			{"id": 1, "constraints": []},
+			{"id": 2, "constraints": "invalid"}
+		]
+	}`
+
+	resp, invalid, err := DecodeFeatureResponse(strings.NewReader(payload))
+	assert.NoError(err)
+	assert.Equal(2, resp.Version)
+
+	if assert.Len(resp.Features, 2) {
+		assert.Equal("valid", resp.Features[0].Name)
+		assert.Equal("future", resp.Features[1].Name)
+	}
+	if assert.Len(resp.Segments, 1) {
+		assert.Equal(1, resp.Segments[0].Id)
+	}
+
+	if assert.Len(invalid, 3) {
+		assert.Equal("bad-stickiness", invalid[0].(*InvalidFeatureError).Feature)
+		assert.Equal("$.features[1].strategies[0].parameters.stickiness", invalid[0].(*InvalidFeatureError).Path)
+		assert.Equal("bad-type", invalid[1].(*InvalidFeatureError).Feature)
+		assert.Equal("$.features[2].enabled", invalid[1].(*InvalidFeatureError).Path)
+		assert.Equal(2, invalid[2].(*InvalidSegmentError).Segment)
+		assert.Equal("$.segments[1].constraints", invalid[2].(*InvalidSegmentError).Path)
+	}
+
+	_, _, err = DecodeFeatureResponse(strings.NewReader(`{"features": {}}`))
+	assert.Error(err)
+}
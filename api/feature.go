@@ -52,6 +52,33 @@ type Feature struct {
 
 	// Dependencies is a list of feature toggle dependency objects
 	Dependencies *[]Dependency `json:"dependencies"`
+
+	// ImpressionData indicates whether impression events should be emitted when
+	// the feature toggle is queried.
+	ImpressionData bool `json:"impressionData"`
+
+	// Project is the project of the feature toggle.
+	Project string `json:"project"`
+
+	// Stale indicates whether the feature toggle was marked as stale.
+	Stale bool `json:"stale"`
+
+	// Tags is a list of tags of the feature toggle.
+	Tags []Tag `json:"tags"`
+}
+
+// Tag is a tag of a feature toggle.
+type Tag struct {
+	// Type is the type of the tag, such as "simple".
+	Type string `json:"type"`
+
+	// Value is the value of the tag.
+	Value string `json:"value"`
+}
+
+// String formats the tag as type:value.
+func (t Tag) String() string {
+	return t.Type + ":" + t.Value
 }
 
 type Dependency struct {
@@ -2,8 +2,9 @@ package unleash
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
+	"log/slog"
+	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
 )
@@ -11,6 +12,14 @@ import (
 type BootstrapStorage struct {
 	backingStore DefaultStorage
 	Reader       io.Reader
+
+	// Logger receives the errors of loading the bootstrap data. If nil, the
+	// client sets it to its own logger.
+	Logger *slog.Logger
+
+	// bootstrapped indicates whether the data was loaded from the reader rather
+	// than from a backup.
+	bootstrapped bool
 }
 
 func (bs *BootstrapStorage) Load() error {
@@ -25,6 +34,7 @@ func (bs *BootstrapStorage) Load() error {
 	}
 
 	bs.backingStore.data = clientFeatures.FeatureMap()
+	bs.bootstrapped = true
 	return nil
 }
 
@@ -33,7 +43,9 @@ func (bs *BootstrapStorage) Init(backupPath string, appName string) {
 	err := bs.Load()
 
 	if err != nil {
-		fmt.Printf("Could not load bootstrap storage, because: %s", err.Error())
+		loggerOrDiscard(bs.Logger).Error("could not load bootstrap storage",
+			slog.String("error", err.Error()),
+			slog.String("app_name", appName))
 		return
 	}
 }
@@ -50,6 +62,15 @@ func (bs *BootstrapStorage) Get(key string) (interface{}, bool) {
 	return bs.backingStore.Get(key)
 }
 
+// savedAt returns the time the backup was written when it was loaded rather than
+// the bootstrap data, whose age is unknown.
+func (bs *BootstrapStorage) savedAt() time.Time {
+	if bs.bootstrapped {
+		return time.Time{}
+	}
+	return bs.backingStore.savedAt()
+}
+
 func (bs *BootstrapStorage) List() []interface{} {
 	return bs.backingStore.List()
 }
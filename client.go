@@ -2,15 +2,18 @@ package unleash
 
 import (
 	"fmt"
-
+	"log/slog"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
 	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
 	"github.com/Unleash/unleash-client-go/v4/internal/constraints"
-	s "github.com/Unleash/unleash-client-go/v4/internal/strategies"
 	"github.com/Unleash/unleash-client-go/v4/strategy"
 )
 
@@ -20,119 +23,182 @@ const (
 	clientVersion    = "4.1.0"
 )
 
-var defaultStrategies = []strategy.Strategy{
-	*s.NewDefaultStrategy(),
-	*s.NewApplicationHostnameStrategy(),
-	*s.NewGradualRolloutRandomStrategy(),
-	*s.NewGradualRolloutSessionId(),
-	*s.NewGradualRolloutUserId(),
-	*s.NewRemoteAddressStrategy(),
-	*s.NewUserWithIdStrategy(),
-	*s.NewFlexibleRolloutStrategy(),
-}
-
-// disabledVariantFeatureEnabled is similar to api.DISABLED_VARIANT but we want
-// to discourage public usage so it's internal until there's a need to expose it.
-var disabledVariantFeatureEnabled = &api.Variant{
-	Name:           "disabled",
-	Enabled:        false,
-	FeatureEnabled: true,
-}
-
 // Client is a structure representing an API client of an Unleash server.
 type Client struct {
 	errorChannels
-	options            configOption
-	repository         *repository
-	metrics            *metrics
-	strategies         []strategy.Strategy
-	errorListener      ErrorListener
-	metricsListener    MetricListener
-	repositoryListener RepositoryListener
-	ready              chan bool
-	onReady            chan struct{}
-	close              chan struct{}
-	closed             chan struct{}
-	count              chan metric
-	sent               chan MetricsData
-	registered         chan ClientData
-	staticContext      *context.Context
+	options         configOption
+	repository      *repository
+	view            *repositoryView
+	metrics         *metrics
+	strategies      []strategy.Strategy
+	bus             eventBus
+	events          *eventQueue[Event]
+	ready           chan bool
+	onReady         chan struct{}
+	close           chan struct{}
+	closed          chan struct{}
+	count           *eventQueue[metric]
+	sent            *eventQueue[MetricsData]
+	registered      chan ClientData
+	staticContext   *contextProviders
+	providersWg     sync.WaitGroup
+	unknownReported sync.Map
+	errorLimiter    *errorLimiter
+
+	// closeOnce makes closing the client idempotent, and isClosed is set once
+	// it starts closing.
+	closeOnce sync.Once
+	isClosed  atomic.Bool
 }
 
 type errorChannels struct {
-	errors   chan error
-	warnings chan error
+	errors   *eventQueue[error]
+	warnings *eventQueue[error]
+	logger   *slog.Logger
 }
 
 func (ec errorChannels) warn(err error) {
-	ec.warnings <- err
+	ec.logger.Warn("unleash warning", errorAttrs(err)...)
+	ec.warnings.push(err)
 }
 
 func (ec errorChannels) err(err error) {
-	ec.errors <- err
+	ec.logger.Error("unleash error", errorAttrs(err)...)
+	ec.errors.push(err)
+}
+
+// report reports the errors and warnings of an evaluation. The reports of an
+// evaluation made from an event handler never wait for room in the queues, which
+// only the goroutine running the handler drains.
+func (ec errorChannels) report(err error, warning bool, fromHandler bool) {
+	if warning {
+		ec.logger.Warn("unleash warning", errorAttrs(err)...)
+	} else {
+		ec.logger.Error("unleash error", errorAttrs(err)...)
+	}
+	queue := ec.errors
+	if warning {
+		queue = ec.warnings
+	}
+	if fromHandler {
+		queue.tryPush(err)
+	} else {
+		queue.push(err)
+	}
 }
 
 type repositoryChannels struct {
 	errorChannels
-	ready chan bool
+	ready  chan bool
+	events *eventQueue[Event]
 }
 
 type metricsChannels struct {
 	errorChannels
-	count      chan metric
-	sent       chan MetricsData
+	count      *eventQueue[metric]
+	sent       *eventQueue[MetricsData]
 	registered chan ClientData
 }
 
-// NewClient creates a new client instance with the given options.
-func NewClient(options ...ConfigOption) (*Client, error) {
+// defaultConfig returns the configuration before the options are applied.
+func defaultConfig() configOption {
+	return configOption{
+		environment:     "default",
+		refreshInterval: 15 * time.Second,
+		metricsInterval: 60 * time.Second,
+		disableMetrics:  false,
+		backupPath:      getTmpDirPath(),
+		strategies:      []strategy.Strategy{},
+		clock:           clock.Real(),
+		errorInterval:   time.Minute,
+		eventBufferSize: defaultEventBufferSize,
+	}
+}
 
-	errChannels := errorChannels{
-		errors:   make(chan error, 3),
-		warnings: make(chan error, 3),
+// repositoryOptions returns the options of a repository created from the
+// configuration.
+func (o configOption) repositoryOptions(serverURL url.URL) repositoryOptions {
+	return repositoryOptions{
+		backupPath:      o.backupPath,
+		url:             serverURL,
+		appName:         o.appName,
+		projects:        o.fetchProjects(),
+		namePrefix:      o.namePrefix,
+		tags:            o.tags,
+		instanceId:      o.instanceId,
+		refreshInterval: o.refreshInterval,
+		storage:         o.storage,
+		httpClient:      o.httpClient,
+		customHeaders:   o.customHeaders,
+		clock:           o.clock,
+		maxDataAge:      o.maxDataAge,
+		logger:          o.logger,
+	}
+}
+
+// fetchProjects returns the projects the feature toggles are fetched for.
+func (o configOption) fetchProjects() []string {
+	if o.projectName == "" {
+		return o.projects
 	}
+	return append([]string{o.projectName}, o.projects...)
+}
+
+// validateTags checks that the tags are formatted as type:value.
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if i := strings.Index(tag, ":"); i <= 0 || i == len(tag)-1 {
+			return fmt.Errorf("tag %q must be formatted as type:value", tag)
+		}
+	}
+	return nil
+}
+
+// NewClient creates a new client instance with the given options.
+func NewClient(options ...ConfigOption) (*Client, error) {
 
 	uc := &Client{
-		options: configOption{
-			environment:     "default",
-			refreshInterval: 15 * time.Second,
-			metricsInterval: 60 * time.Second,
-			disableMetrics:  false,
-			backupPath:      getTmpDirPath(),
-			strategies:      []strategy.Strategy{},
-		},
-		errorChannels: errChannels,
-		onReady:       make(chan struct{}),
-		ready:         make(chan bool, 1),
-		count:         make(chan metric),
-		sent:          make(chan MetricsData),
-		registered:    make(chan ClientData, 1),
-		close:         make(chan struct{}),
-		closed:        make(chan struct{}),
+		options:    defaultConfig(),
+		onReady:    make(chan struct{}),
+		ready:      make(chan bool, 1),
+		registered: make(chan ClientData, 1),
+		close:      make(chan struct{}),
+		closed:     make(chan struct{}),
 	}
 
 	for _, opt := range options {
 		opt(&uc.options)
 	}
 
-	uc.staticContext = &context.Context{
+	shared := uc.options.sharedRepository
+	if shared != nil {
+		shared.inherit(&uc.options)
+	}
+
+	size, policy := uc.options.eventBufferSize, uc.options.overflowPolicy
+	uc.options.logger = loggerOrDiscard(uc.options.logger)
+	errChannels := errorChannels{
+		errors:   newEventQueue[error](size, policy),
+		warnings: newEventQueue[error](size, policy),
+		logger:   uc.options.logger,
+	}
+	uc.errorChannels = errChannels
+	uc.count = newEventQueue[metric](size, policy)
+	uc.sent = newEventQueue[MetricsData](size, policy)
+	uc.events = newEventQueue[Event](size, policy)
+
+	uc.errorLimiter = newErrorLimiter(uc.options.clock, uc.options.errorInterval)
+
+	uc.staticContext = newContextProviders(context.Context{
 		Environment: uc.options.environment,
 		AppName:     uc.options.appName,
-	}
+	}, uc.options.providers)
 
 	if uc.options.listener == nil {
 		uc.options.listener = &NoopListener{}
 	}
 
-	if eListener, ok := uc.options.listener.(ErrorListener); ok {
-		uc.errorListener = eListener
-	}
-	if rListener, ok := uc.options.listener.(RepositoryListener); ok {
-		uc.repositoryListener = rListener
-	}
-	if mListener, ok := uc.options.listener.(MetricListener); ok {
-		uc.metricsListener = mListener
-	}
+	uc.bus.subscribe(listenerHandler(uc.options.listener), nil)
 	defer func() {
 		go uc.sync()
 	}()
@@ -159,29 +225,49 @@ func NewClient(options ...ConfigOption) (*Client, error) {
 		return nil, fmt.Errorf("unleash client appName missing")
 	}
 
+	if err := validateTags(uc.options.tags); err != nil {
+		return nil, err
+	}
+
+	for operator := range uc.options.operators {
+		if constraints.IsBuiltin(operator) {
+			return nil, fmt.Errorf("constraint operator %s is built in and cannot be replaced", operator)
+		}
+	}
+
 	if uc.options.instanceId == "" {
 		uc.options.instanceId = generateInstanceId()
 	}
 
-	uc.repository = newRepository(
-		repositoryOptions{
-			backupPath:      uc.options.backupPath,
-			url:             *parsedUrl,
-			appName:         uc.options.appName,
-			projectName:     uc.options.projectName,
-			instanceId:      uc.options.instanceId,
-			refreshInterval: uc.options.refreshInterval,
-			storage:         uc.options.storage,
-			httpClient:      uc.options.httpClient,
-			customHeaders:   uc.options.customHeaders,
-		},
-		repositoryChannels{
-			errorChannels: errChannels,
-			ready:         uc.ready,
-		},
-	)
+	if shared != nil {
+		if err := shared.repo.acquire(); err != nil {
+			return nil, err
+		}
+		uc.repository = shared.repo
+	} else {
+		if bs, ok := uc.options.storage.(*BootstrapStorage); ok && bs.Logger == nil {
+			bs.Logger = uc.options.logger
+		}
+		uc.repository = newRepository(uc.options.repositoryOptions(*parsedUrl))
+	}
 
-	uc.strategies = append(defaultStrategies, uc.options.strategies...)
+	evalOpts := []evaluator.Option{
+		evaluator.WithStrategies(uc.options.strategies...),
+		evaluator.WithClock(uc.options.clock),
+	}
+	for operator, fn := range uc.options.operators {
+		evalOpts = append(evalOpts, evaluator.WithConstraintOperator(operator, fn))
+	}
+	uc.view = uc.repository.attach(evalOpts, repositoryChannels{
+		errorChannels: errChannels,
+		ready:         uc.ready,
+		events:        uc.events,
+	})
+	if shared == nil {
+		uc.repository.start()
+	}
+
+	uc.strategies = append(evaluator.DefaultStrategies(), uc.options.strategies...)
 
 	strategyNames := make([]string, len(uc.strategies))
 	for i, strategy := range uc.strategies {
@@ -198,6 +284,7 @@ func NewClient(options ...ConfigOption) (*Client, error) {
 			httpClient:      uc.options.httpClient,
 			customHeaders:   uc.options.customHeaders,
 			disableMetrics:  uc.options.disableMetrics,
+			clock:           uc.options.clock,
 		},
 		metricsChannels{
 			errorChannels: errChannels,
@@ -207,50 +294,89 @@ func NewClient(options ...ConfigOption) (*Client, error) {
 		},
 	)
 
+	uc.staticContext.start(uc.options.clock, uc.close, &uc.providersWg)
+
 	return uc, nil
 }
 
 func (uc *Client) sync() {
 	for {
 		select {
-		case e := <-uc.errors:
-			if uc.errorListener != nil {
-				uc.errorListener.OnError(e)
-			}
-		case w := <-uc.warnings:
-			if uc.errorListener != nil {
-				uc.errorListener.OnWarning(w)
+		case <-uc.close:
+			// Deliver the events queued before the client was closed.
+			for uc.dispatchQueued() {
 			}
+			close(uc.closed)
+			return
+		default:
+		}
+
+		select {
+		case e := <-uc.errors.ch:
+			uc.bus.publish(ErrorEvent{Err: e})
+		case w := <-uc.warnings.ch:
+			uc.bus.publish(WarningEvent{Err: w})
 		case <-uc.ready:
 			close(uc.onReady)
-			if uc.repositoryListener != nil {
-				uc.repositoryListener.OnReady()
-			}
-		case m := <-uc.count:
-			if uc.metricsListener != nil {
-				uc.metricsListener.OnCount(m.Name, m.Enabled)
-			}
-		case md := <-uc.sent:
-			if uc.metricsListener != nil {
-				uc.metricsListener.OnSent(md)
-			}
+			uc.bus.publish(ReadyEvent{})
+		case m := <-uc.count.ch:
+			uc.bus.publish(CountEvent{Feature: m.Name, Enabled: m.Enabled})
+		case md := <-uc.sent.ch:
+			uc.bus.publish(SentEvent{Payload: md})
 		case cd := <-uc.registered:
-			if uc.metricsListener != nil {
-				uc.metricsListener.OnRegistered(cd)
-			}
+			uc.bus.publish(RegisteredEvent{Payload: cd})
+		case e := <-uc.events.ch:
+			uc.bus.publish(e)
 		case <-uc.close:
-			close(uc.closed)
-			return
 		}
 	}
 }
 
+// dispatchQueued delivers one queued event, if any, and reports whether it did.
+func (uc *Client) dispatchQueued() bool {
+	select {
+	case e := <-uc.errors.ch:
+		uc.bus.publish(ErrorEvent{Err: e})
+	case w := <-uc.warnings.ch:
+		uc.bus.publish(WarningEvent{Err: w})
+	case m := <-uc.count.ch:
+		uc.bus.publish(CountEvent{Feature: m.Name, Enabled: m.Enabled})
+	case md := <-uc.sent.ch:
+		uc.bus.publish(SentEvent{Payload: md})
+	case e := <-uc.events.ch:
+		uc.bus.publish(e)
+	default:
+		return false
+	}
+	return true
+}
+
+// Subscribe registers a handler for the events of the client. If event types are
+// given, only events of those types are delivered to the handler. Any number of
+// handlers can be subscribed and unsubscribed at any time, including from within
+// a handler. The returned function removes the subscription; it is safe to call
+// it more than once.
+//
+// Handlers run one at a time on the goroutine delivering the events. With
+// OverflowBlock, a handler must not evaluate feature toggles, since the
+// evaluation may wait for that goroutine to make room in a full queue; hand the
+// event over to another goroutine instead, or use Watch.
+//
+// The listener registered with WithListener is itself delivered through a
+// subscription made when the client is created, so it receives every event
+// first, followed by the handlers in the order they were subscribed.
+func (uc *Client) Subscribe(handler EventHandler, types ...EventType) (unsubscribe func()) {
+	return uc.bus.subscribe(handler, types)
+}
+
 // IsEnabled queries whether the specified feature is enabled or not.
 //
 // It is safe to call this method from multiple goroutines concurrently.
 func (uc *Client) IsEnabled(feature string, options ...FeatureOption) (enabled bool) {
 	defer func() {
-		uc.metrics.count(feature, enabled)
+		if !uc.isClosed.Load() {
+			uc.metrics.count(feature, enabled)
+		}
 	}()
 
 	result, _ := uc.isEnabled(feature, options...)
@@ -258,130 +384,110 @@ func (uc *Client) IsEnabled(feature string, options ...FeatureOption) (enabled b
 }
 
 // isEnabled abstracts away the details of checking if a toggle is turned on or off
-// without metrics
+// without counting the evaluation in the metrics
 func (uc *Client) isEnabled(feature string, options ...FeatureOption) (api.StrategyResult, *api.Feature) {
 	var opts featureOption
 	for _, o := range options {
 		o(&opts)
 	}
 
-	f := resolveToggle(uc, opts, feature)
-
-	ctx := uc.staticContext
-	if opts.ctx != nil {
-		ctx = ctx.Override(*opts.ctx)
+	ctx := uc.context(opts.ctx)
+	result, bypassed := uc.evaluate(feature, opts, ctx, false)
+	if result.Feature == nil {
+		if !bypassed {
+			uc.reportUnknown(feature)
+		}
+		return api.StrategyResult{
+			Enabled: uc.fallback(feature, opts, ctx),
+		}, nil
 	}
 
-	if f == nil {
-		return handleFallback(opts, feature, ctx), nil
+	if result.Feature.ImpressionData {
+		uc.impression(ImpressionEvent{
+			EventType: "isEnabled",
+			Feature:   feature,
+			Enabled:   result.Enabled,
+			Context:   *ctx,
+		})
 	}
+	return api.StrategyResult{
+		Enabled: result.Enabled,
+		Variant: result.Variant,
+	}, result.Feature
+}
 
-	if f.Dependencies != nil && len(*f.Dependencies) > 0 {
-		dependenciesSatisfied := uc.isParentDependencySatisfied(f, *ctx)
-
-		if !dependenciesSatisfied {
-			return api.StrategyResult{
-				Enabled: false,
-			}, f
-		}
-	}
+// impression queues an impression event for the subscribers of the client.
+func (uc *Client) impression(e ImpressionEvent) {
+	uc.events.push(e)
+}
 
-	if !f.Enabled {
-		return api.StrategyResult{
-			Enabled: false,
-		}, f
+// context layers the context passed to a call over the static context.
+func (uc *Client) context(ctx *context.Context) *context.Context {
+	static := uc.staticContext.get()
+	if ctx == nil {
+		return static
 	}
+	return static.Override(*ctx, context.MergeProperties())
+}
 
-	if len(f.Strategies) == 0 {
-		return api.StrategyResult{
-			Enabled: f.Enabled,
-		}, f
+// evaluate resolves the feature toggle and evaluates it, reporting the errors and
+// warnings of the evaluation. If variant is true the variant is selected too.
+// While the data of the repository is stale, the result follows the stale
+// policy. Once the client is closed, every feature toggle is treated as not
+// found. bypassed reports whether the feature toggle was treated as not found
+// for either reason, in which case it must not be reported as unknown.
+func (uc *Client) evaluate(feature string, opts featureOption, ctx *context.Context, variant bool) (result evaluator.Result, bypassed bool) {
+	if uc.isClosed.Load() {
+		uc.reportClosed(opts)
+		return evaluator.Result{}, true
 	}
 
-	for _, s := range f.Strategies {
-		foundStrategy := uc.getStrategy(s.Name)
-		if foundStrategy == nil {
-			// TODO: warnOnce missingStrategy
-			continue
+	var evalOpts []evaluator.EvaluationOption
+	if opts.resolver != nil {
+		f := opts.resolver(feature)
+		if f == nil {
+			opts.explain("feature toggle not found")
+			return evaluator.Result{}, false
 		}
+		evalOpts = append(evalOpts, evaluator.WithFeature(f))
+	}
+	if opts.reasons != nil {
+		evalOpts = append(evalOpts, evaluator.WithReasons())
+	}
 
-		segmentConstraints, err := uc.repository.resolveSegmentConstraints(s)
+	if variant {
+		result = uc.view.evaluator().Variant(feature, ctx, evalOpts...)
+	} else {
+		result = uc.view.evaluator().Evaluate(feature, ctx, evalOpts...)
+	}
 
-		if err != nil {
-			uc.errors <- err
-			return api.StrategyResult{
-				Enabled: false,
-			}, f
+	for _, err := range result.Errors {
+		if uc.errorLimiter.allow(err) {
+			uc.report(err, false, opts.fromHandler)
 		}
-
-		allConstraints := make([]api.Constraint, 0)
-		allConstraints = append(allConstraints, segmentConstraints...)
-		allConstraints = append(allConstraints, s.Constraints...)
-
-		if ok, err := constraints.Check(ctx, allConstraints); err != nil {
-			uc.errors <- err
-		} else if ok && foundStrategy.IsEnabled(s.Parameters, ctx) {
-			if s.Variants != nil && len(s.Variants) > 0 {
-				groupIdValue := s.Parameters[strategy.ParamGroupId]
-				groupId, ok := groupIdValue.(string)
-				if !ok {
-					return api.StrategyResult{
-						Enabled: false,
-					}, f
-				}
-
-				return api.StrategyResult{
-					Enabled: true,
-					Variant: api.VariantCollection{
-						GroupId:  groupId,
-						Variants: s.Variants,
-					}.GetVariant(ctx),
-				}, f
-			} else {
-				return api.StrategyResult{
-					Enabled: true,
-				}, f
-			}
+	}
+	for _, warning := range result.Warnings {
+		if uc.errorLimiter.allow(warning) {
+			uc.report(warning, true, opts.fromHandler)
 		}
 	}
-
-	return api.StrategyResult{
-		Enabled: false,
-	}, f
+	if opts.reasons != nil {
+		*opts.reasons = append(*opts.reasons, result.Reasons...)
+	}
+	if opts.resolver == nil {
+		bypassed = uc.applyStalePolicy(opts, &result)
+	}
+	return result, bypassed
 }
 
-func (uc *Client) isParentDependencySatisfied(feature *api.Feature, context context.Context) bool {
-	warnOnce := &WarnOnce{}
-
-	dependenciesSatisfied := func(parent api.Dependency) bool {
-		parentToggle := uc.repository.getToggle(parent.Feature)
-
-		if parentToggle == nil {
-			warnOnce.Warn("the parent toggle was not found in the cache, the evaluation of this dependency will always be false")
-			return false
-		}
-
-		if parentToggle.Dependencies != nil && len(*parentToggle.Dependencies) > 0 {
-			return false
-		}
-
-		enabledResult, _ := uc.isEnabled(parent.Feature, WithContext(context))
-		// According to the schema, if the enabled property is absent we assume it's true.
-		if parent.Enabled == nil || *parent.Enabled {
-			if parent.Variants != nil && len(*parent.Variants) > 0 && enabledResult.Variant != nil {
-				return enabledResult.Enabled && contains(*parent.Variants, enabledResult.Variant.Name)
-			}
-			return enabledResult.Enabled
-		}
-
-		return !enabledResult.Enabled
+// fallback returns the value of a feature toggle that was not found.
+func (uc *Client) fallback(feature string, opts featureOption, ctx *context.Context) bool {
+	if d, ok := uc.featureDefault(opts, feature); ok {
+		opts.explain("using the declared default")
+		return d.Enabled
 	}
-
-	allDependenciesSatisfied := every(*feature.Dependencies, func(parent interface{}) bool {
-		return dependenciesSatisfied(parent.(api.Dependency))
-	})
-
-	return allDependenciesSatisfied
+	opts.explain("using the fallback")
+	return handleFallback(opts, feature, ctx).Enabled
 }
 
 // GetVariant queries a variant as the specified feature is enabled.
@@ -390,87 +496,116 @@ func (uc *Client) isParentDependencySatisfied(feature *api.Feature, context cont
 func (uc *Client) GetVariant(feature string, options ...VariantOption) *api.Variant {
 	variant := uc.getVariantWithoutMetrics(feature, options...)
 	defer func() {
-		uc.metrics.countVariants(feature, variant.FeatureEnabled, variant.Name)
+		if !uc.isClosed.Load() {
+			uc.metrics.countVariants(feature, variant.FeatureEnabled, variant.Name)
+		}
 	}()
 	return variant
 }
 
 // getVariantWithoutMetrics abstracts away the logic for resolving a variant without metrics
 func (uc *Client) getVariantWithoutMetrics(feature string, options ...VariantOption) *api.Variant {
-	defaultVariant := api.GetDefaultVariant()
 	var opts variantOption
 	for _, o := range options {
 		o(&opts)
 	}
 
-	ctx := uc.staticContext
-	if opts.ctx != nil {
-		ctx = ctx.Override(*opts.ctx)
+	ctx := uc.context(opts.ctx)
+	result, bypassed := uc.evaluate(feature, featureOption{resolver: opts.resolver}, ctx, true)
+	if result.Feature == nil && !bypassed {
+		uc.reportUnknown(feature)
 	}
-
-	var strategyResult api.StrategyResult
-	var f *api.Feature
-	if opts.resolver != nil {
-		strategyResult, f = uc.isEnabled(feature, WithContext(*ctx), WithResolver(opts.resolver))
-	} else {
-		strategyResult, f = uc.isEnabled(feature, WithContext(*ctx))
+	variant := uc.variant(feature, opts, ctx, result)
+	if result.Feature != nil && result.Feature.ImpressionData {
+		uc.impression(ImpressionEvent{
+			EventType: "getVariant",
+			Feature:   feature,
+			Enabled:   variant.FeatureEnabled,
+			Variant:   variant.Name,
+			Context:   *ctx,
+		})
 	}
+	return variant
+}
 
-	getFallbackVariant := func(featureEnabled bool) *api.Variant {
+// variant applies the fallbacks of a variant query to the result of an evaluation.
+func (uc *Client) variant(feature string, opts variantOption, ctx *context.Context, result evaluator.Result) *api.Variant {
+	return result.ResolveVariant(func(featureEnabled bool) *api.Variant {
 		if opts.variantFallbackFunc != nil {
 			return opts.variantFallbackFunc(feature, ctx)
 		} else if opts.variantFallback != nil {
 			return opts.variantFallback
 		}
 
-		if featureEnabled {
-			return disabledVariantFeatureEnabled
+		if result.Feature == nil {
+			if d, ok := uc.options.featureDefaults[feature]; ok && d.Variant != nil {
+				// Copied so that a caller modifying the variant does not change
+				// the declared default.
+				v := *d.Variant
+				return &v
+			}
 		}
-		return defaultVariant
-	}
+		return nil
+	})
+}
 
-	if !strategyResult.Enabled {
-		return getFallbackVariant(false)
-	}
+// EvaluationDetails describes the outcome of evaluating a feature toggle and how
+// it was reached.
+type EvaluationDetails struct {
+	// Feature is the name of the feature toggle.
+	Feature string `json:"feature"`
 
-	if f == nil || !f.Enabled {
-		return getFallbackVariant(false)
-	}
+	// Found indicates whether the feature toggle was found.
+	Found bool `json:"found"`
 
-	if strategyResult.Variant != nil {
-		return strategyResult.Variant
-	}
+	// Enabled is the value IsEnabled returns for the same options.
+	Enabled bool `json:"enabled"`
 
-	if len(f.Variants) == 0 {
-		return getFallbackVariant(true)
-	}
+	// Variant is the variant GetVariant returns for the same context.
+	Variant *api.Variant `json:"variant"`
 
-	return api.VariantCollection{
-		GroupId:  f.Name,
-		Variants: f.Variants,
-	}.GetVariant(ctx)
+	// Reasons lists the steps of the evaluation in order.
+	Reasons []string `json:"reasons"`
 }
 
-// Close stops the client from syncing data from the server.
-func (uc *Client) Close() error {
-	uc.repository.Close()
-	uc.metrics.Close()
-	if uc.options.listener != nil {
-		// Wait for sync to exit.
-		close(uc.close)
-		<-uc.closed
+// Explain evaluates the specified feature like IsEnabled and GetVariant do and
+// reports why it resolved to its value. The evaluation is not counted in the
+// metrics.
+//
+// It is safe to call this method from multiple goroutines concurrently.
+func (uc *Client) Explain(feature string, options ...FeatureOption) EvaluationDetails {
+	var reasons []string
+	var opts featureOption
+	for _, o := range options {
+		o(&opts)
+	}
+	opts.reasons = &reasons
+
+	ctx := uc.context(opts.ctx)
+	result, _ := uc.evaluate(feature, opts, ctx, true)
+
+	enabled := result.Enabled
+	if result.Feature == nil {
+		enabled = uc.fallback(feature, opts, ctx)
+	}
+
+	return EvaluationDetails{
+		Feature: feature,
+		Found:   result.Feature != nil,
+		Enabled: enabled,
+		Variant: uc.variant(feature, variantOption{resolver: opts.resolver}, ctx, result),
+		Reasons: reasons,
 	}
-	return nil
 }
 
 // Errors returns the error channel for the client.
 func (uc *Client) Errors() <-chan error {
-	return uc.errors
+	return uc.errors.ch
 }
 
 // Warnings returns the warnings channel for the client.
 func (uc *Client) Warnings() <-chan error {
-	return uc.warnings
+	return uc.warnings.ch
 }
 
 // Ready returns the ready channel for the client. A value will be available on
@@ -482,7 +617,7 @@ func (uc *Client) Ready() <-chan bool {
 
 // Count returns the count channel which gives an update when a toggle has been queried.
 func (uc *Client) Count() <-chan metric {
-	return uc.count
+	return uc.count.ch
 }
 
 // Registered returns the registered signal indicating that the client has successfully connected to the
@@ -494,16 +629,19 @@ func (uc *Client) Registered() <-chan ClientData {
 // Sent returns the sent channel which receives data whenever the client has successfully sent metrics to
 // the metrics service.
 func (uc *Client) Sent() <-chan MetricsData {
-	return uc.sent
+	return uc.sent.ch
 }
 
-func (uc *Client) getStrategy(name string) strategy.Strategy {
-	for _, strategy := range uc.strategies {
-		if strategy.Name() == name {
-			return strategy
-		}
+// DroppedEvents returns how many events were discarded so far because their
+// queue was full. See WithEventOverflowPolicy.
+func (uc *Client) DroppedEvents() DroppedEvents {
+	return DroppedEvents{
+		Errors:   uc.errors.droppedCount(),
+		Warnings: uc.warnings.droppedCount(),
+		Counts:   uc.count.droppedCount(),
+		Sent:     uc.sent.droppedCount(),
+		Events:   uc.events.droppedCount(),
 	}
-	return nil
 }
 
 // WaitForReady will block until the client has loaded the feature toggles from
@@ -515,20 +653,43 @@ func (uc *Client) WaitForReady() {
 	<-uc.onReady
 }
 
-// ListFeatures returns all available features toggles.
-func (uc *Client) ListFeatures() []api.Feature {
-	return uc.repository.list()
+// ListFeatures returns all available features toggles, or only those matching
+// every given filter.
+func (uc *Client) ListFeatures(filters ...FeatureFilter) []api.Feature {
+	return filterFeatures(uc.repository.list(), filters)
 }
 
-func resolveToggle(unleashClient *Client, opts featureOption, featureName string) *api.Feature {
-	var feature *api.Feature
-	if opts.resolver != nil {
-		feature = opts.resolver(featureName)
-	} else {
-		feature = unleashClient.repository.getToggle(featureName)
+// featureDefault returns the declared default of a feature toggle, unless a
+// fallback was passed explicitly with the call.
+func (uc *Client) featureDefault(opts featureOption, feature string) (Default, bool) {
+	if opts.fallbackFunc != nil || opts.fallback != nil {
+		return Default{}, false
 	}
+	d, ok := uc.options.featureDefaults[feature]
+	return d, ok
+}
 
-	return feature
+// reportUnknown counts the evaluation of a toggle missing from the repository and,
+// in strict mode, warns once about toggles that have no declared default either.
+// Nothing is reported until the client is ready, as every toggle is missing
+// before the first fetch.
+func (uc *Client) reportUnknown(feature string) {
+	select {
+	case <-uc.onReady:
+	default:
+		return
+	}
+	uc.metrics.countUnknown(feature)
+
+	if !uc.options.strictMode {
+		return
+	}
+	if _, declared := uc.options.featureDefaults[feature]; declared {
+		return
+	}
+	if _, reported := uc.unknownReported.LoadOrStore(feature, struct{}{}); !reported {
+		uc.warn(fmt.Errorf("feature toggle %q is not defined in the repository nor declared with WithFeatureDefaults", feature))
+	}
 }
 
 func handleFallback(opts featureOption, featureName string, ctx *context.Context) api.StrategyResult {
@@ -0,0 +1,330 @@
+package unleash
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/clock"
+)
+
+var (
+	// ErrTooManyClients is returned by ClientManager.Client when creating a
+	// client would exceed the maximum set with WithMaxClients.
+	ErrTooManyClients = errors.New("too many clients")
+
+	// ErrManagerClosed is returned by ClientManager.Client once the manager is
+	// closed.
+	ErrManagerClosed = errors.New("client manager is closed")
+)
+
+// ClientKey identifies a client of a ClientManager.
+type ClientKey struct {
+	// Token is the API token sent in the Authorization header.
+	Token string
+
+	// Project is the project the feature toggles are fetched for, or empty for
+	// every project the token gives access to.
+	Project string
+}
+
+// evictShutdownTimeout bounds the time spent sending the pending metrics of an
+// evicted client.
+const evictShutdownTimeout = 10 * time.Second
+
+// ManagerOption represents an option for configuring a ClientManager.
+type ManagerOption func(*managerOptions)
+
+type managerOptions struct {
+	clientOptions []ConfigOption
+	maxClients    int
+	idleTimeout   time.Duration
+	clock         clock.Clock
+}
+
+// WithClientOptions sets the options shared by every client created by the
+// manager, such as WithUrl and WithAppName.
+//
+// Unless a storage is set with WithStorage, each client backs up its feature
+// toggles in its own file of the backup path. A storage set here is shared by
+// every client, and so are the feature toggles it holds.
+func WithClientOptions(options ...ConfigOption) ManagerOption {
+	return func(o *managerOptions) {
+		o.clientOptions = append(o.clientOptions, options...)
+	}
+}
+
+// WithMaxClients sets the maximum number of clients of the manager. By default
+// there is no maximum.
+func WithMaxClients(n int) ManagerOption {
+	return func(o *managerOptions) {
+		o.maxClients = n
+	}
+}
+
+// WithIdleTimeout sets how long a client may go without being requested before
+// it is closed and removed from the manager. By default clients are never
+// evicted.
+func WithIdleTimeout(timeout time.Duration) ManagerOption {
+	return func(o *managerOptions) {
+		o.idleTimeout = timeout
+	}
+}
+
+// WithManagerClock sets the clock used to evict idle clients. Defaults to the
+// system clock.
+func WithManagerClock(c clock.Clock) ManagerOption {
+	return func(o *managerOptions) {
+		o.clock = c
+	}
+}
+
+// ClientManagerStats describes the clients of a ClientManager.
+type ClientManagerStats struct {
+	// Active is the number of clients.
+	Active int
+
+	// Failing lists the clients whose last fetch of the feature toggles failed,
+	// sorted by token and project.
+	Failing []ClientKey
+
+	// Created is the number of clients created so far.
+	Created uint64
+
+	// Evicted is the number of clients shut down because they were idle.
+	Evicted uint64
+}
+
+// managedClient is a client of the manager. The client is created outside of the
+// lock of the manager; ready is closed once it is.
+type managedClient struct {
+	ready    chan struct{}
+	client   *Client
+	err      error
+	lastUsed time.Time
+}
+
+// ClientManager lazily creates one client per API token and project, for services
+// serving many Unleash projects. The clients share the options set with
+// WithClientOptions, and are shut down when idle for longer than the timeout set
+// with WithIdleTimeout.
+//
+// Since idle clients are closed, request a client from the manager each time it
+// is used rather than keeping it.
+type ClientManager struct {
+	options managerOptions
+	mu      sync.Mutex
+	clients map[ClientKey]*managedClient
+	created uint64
+	evicted uint64
+	closed  bool
+	close   chan struct{}
+	done    chan struct{}
+}
+
+// NewClientManager creates a client manager with the given options.
+func NewClientManager(options ...ManagerOption) *ClientManager {
+	m := &ClientManager{
+		options: managerOptions{clock: clock.Real()},
+		clients: map[ClientKey]*managedClient{},
+		close:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(&m.options)
+	}
+
+	if m.options.idleTimeout > 0 {
+		go m.evictIdle()
+	} else {
+		close(m.done)
+	}
+	return m
+}
+
+// Client returns the client for the API token and project, creating it on first
+// use.
+//
+// It is safe to call this method from multiple goroutines concurrently.
+func (m *ClientManager) Client(token, project string) (*Client, error) {
+	key := ClientKey{Token: token, Project: project}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrManagerClosed
+	}
+	mc, ok := m.clients[key]
+	if !ok {
+		if m.options.maxClients > 0 && len(m.clients) >= m.options.maxClients {
+			m.mu.Unlock()
+			return nil, ErrTooManyClients
+		}
+		mc = &managedClient{ready: make(chan struct{})}
+		m.clients[key] = mc
+	}
+	mc.lastUsed = m.options.clock.Now()
+	m.mu.Unlock()
+
+	if ok {
+		<-mc.ready
+		return mc.client, mc.err
+	}
+
+	mc.client, mc.err = NewClient(m.clientOptions(key)...)
+	m.mu.Lock()
+	if mc.err != nil {
+		delete(m.clients, key)
+	} else {
+		m.created++
+	}
+	m.mu.Unlock()
+	close(mc.ready)
+	return mc.client, mc.err
+}
+
+// clientOptions returns the options of the client for the key.
+func (m *ClientManager) clientOptions(key ClientKey) []ConfigOption {
+	var shared configOption
+	for _, opt := range m.options.clientOptions {
+		opt(&shared)
+	}
+	headers := http.Header{}
+	for k, v := range shared.customHeaders {
+		headers[k] = v
+	}
+	headers.Set("Authorization", key.Token)
+
+	options := append([]ConfigOption{}, m.options.clientOptions...)
+	options = append(options, WithCustomHeaders(headers))
+	if key.Project != "" {
+		options = append(options, WithProjectName(key.Project))
+	}
+	if shared.storage == nil {
+		options = append(options, WithStorage(&keyedStorage{
+			Storage: &DefaultStorage{},
+			key:     key.hash(),
+		}))
+	}
+	return options
+}
+
+// hash identifies the key in file names without revealing the token.
+func (k ClientKey) hash() string {
+	sum := sha256.Sum256([]byte(k.Token + "\x00" + k.Project))
+	return hex.EncodeToString(sum[:8])
+}
+
+// keyedStorage keeps the backup of a client of a manager apart from the backups
+// of the other clients, which share the backup path and app name.
+type keyedStorage struct {
+	Storage
+	key string
+}
+
+func (s *keyedStorage) Init(backupPath, appName string) {
+	s.Storage.Init(backupPath, appName+"-"+s.key)
+}
+
+func (s *keyedStorage) savedAt() time.Time {
+	if ds, ok := s.Storage.(datedStorage); ok {
+		return ds.savedAt()
+	}
+	return time.Time{}
+}
+
+// Stats describes the clients of the manager.
+func (m *ClientManager) Stats() ClientManagerStats {
+	m.mu.Lock()
+	stats := ClientManagerStats{Created: m.created, Evicted: m.evicted}
+	clients := map[ClientKey]*Client{}
+	for key, mc := range m.clients {
+		select {
+		case <-mc.ready:
+			clients[key] = mc.client
+		default:
+		}
+	}
+	stats.Active = len(clients)
+	m.mu.Unlock()
+
+	for key, client := range clients {
+		if client.Status().Repository.LastError != "" {
+			stats.Failing = append(stats.Failing, key)
+		}
+	}
+	sort.Slice(stats.Failing, func(i, j int) bool {
+		a, b := stats.Failing[i], stats.Failing[j]
+		if a.Token != b.Token {
+			return a.Token < b.Token
+		}
+		return a.Project < b.Project
+	})
+	return stats
+}
+
+// evictIdle periodically shuts down the clients that were not requested within the
+// idle timeout.
+func (m *ClientManager) evictIdle() {
+	defer close(m.done)
+	ticker := m.options.clock.NewTicker(m.options.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.close:
+			return
+		case <-ticker.C():
+			now := m.options.clock.Now()
+			var idle []*Client
+			m.mu.Lock()
+			for key, mc := range m.clients {
+				select {
+				case <-mc.ready:
+				default:
+					continue
+				}
+				if now.Sub(mc.lastUsed) >= m.options.idleTimeout {
+					idle = append(idle, mc.client)
+					delete(m.clients, key)
+					m.evicted++
+				}
+			}
+			m.mu.Unlock()
+
+			for _, client := range idle {
+				ctx, cancel := context.WithTimeout(context.Background(), evictShutdownTimeout)
+				client.Shutdown(ctx)
+				cancel()
+			}
+		}
+	}
+}
+
+// Close closes every client of the manager. Client returns ErrManagerClosed
+// afterwards.
+func (m *ClientManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	clients := m.clients
+	m.clients = map[ClientKey]*managedClient{}
+	m.mu.Unlock()
+
+	close(m.close)
+	<-m.done
+	for _, mc := range clients {
+		<-mc.ready
+		if mc.client != nil {
+			mc.client.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package unleash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientManager(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") == "revoked" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{{
+			Name:       req.Header.Get("Authorization") + "-" + req.URL.Query().Get("project"),
+			Enabled:    true,
+			Strategies: []api.Strategy{{Name: "default"}},
+		}}})
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	manager := NewClientManager(
+		WithClientOptions(
+			WithUrl(srv.URL),
+			WithAppName(mockAppName),
+			WithInstanceId(mockInstanceId),
+			WithDisableMetrics(true),
+			WithRefreshInterval(time.Hour),
+			WithBackupPath(t.TempDir()),
+			WithCustomHeaders(http.Header{"X-Custom": {"1"}}),
+		),
+		WithMaxClients(3),
+		WithIdleTimeout(10*time.Minute),
+		WithManagerClock(fake),
+	)
+
+	var wg sync.WaitGroup
+	clients := make([]*Client, 10)
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client, err := manager.Client("a", "one")
+			assert.NoError(err)
+			clients[i] = client
+		}(i)
+	}
+	wg.Wait()
+	for _, client := range clients {
+		assert.True(clients[0] == client, "a single client is created per key")
+	}
+	clients[0].WaitForReady()
+	assert.True(clients[0].IsEnabled("a-one"))
+
+	b, err := manager.Client("b", "")
+	assert.NoError(err)
+	b.WaitForReady()
+	assert.True(b.IsEnabled("b-"))
+
+	revoked, err := manager.Client("revoked", "")
+	assert.NoError(err)
+	for revoked.Status().Repository.LastStatusCode == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err = manager.Client("c", "")
+	assert.Equal(ErrTooManyClients, err)
+
+	stats := manager.Stats()
+	assert.Equal(3, stats.Active)
+	assert.Equal(uint64(3), stats.Created)
+	assert.Equal([]ClientKey{{Token: "revoked"}}, stats.Failing)
+
+	// Only the client requested within the idle timeout is kept.
+	fake.Advance(6 * time.Minute)
+	_, err = manager.Client("a", "one")
+	assert.NoError(err)
+	fake.Advance(6 * time.Minute)
+	for manager.Stats().Active != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(uint64(2), manager.Stats().Evicted)
+
+	assert.NoError(manager.Close())
+	assert.NoError(manager.Close())
+	_, err = manager.Client("a", "one")
+	assert.Equal(ErrManagerClosed, err)
+}
+
+func TestClientManager_IsolatesBackups(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "a" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{{
+			Name:       "tenant-a-secret",
+			Enabled:    true,
+			Strategies: []api.Strategy{{Name: "default"}},
+		}}})
+	}))
+	defer srv.Close()
+
+	manager := NewClientManager(WithClientOptions(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithRefreshInterval(time.Hour),
+		WithBackupPath(t.TempDir()),
+	))
+	defer manager.Close()
+
+	a, err := manager.Client("a", "")
+	assert.NoError(err)
+	a.WaitForReady()
+	assert.True(a.IsEnabled("tenant-a-secret"))
+
+	b, err := manager.Client("b", "")
+	assert.NoError(err)
+	for b.Status().Repository.LastStatusCode == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.False(b.IsEnabled("tenant-a-secret"))
+	assert.Equal(DataSourceNone, b.Status().Repository.Source)
+}
@@ -1,13 +1,18 @@
 package unleash
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
 	"github.com/Unleash/unleash-client-go/v4/context"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -503,7 +508,7 @@ func TestClient_WithNonExistingSegment(t *testing.T) {
 	mockListener.On("OnReady").Return()
 	mockListener.On("OnRegistered", mock.AnythingOfType("ClientData"))
 	mockListener.On("OnCount", feature, false).Return()
-	mockListener.On("OnError", mock.AnythingOfType("*errors.errorString"))
+	mockListener.On("OnError", mock.AnythingOfType("*evaluator.SegmentNotFoundError"))
 
 	client, err := NewClient(
 		WithUrl(mockerServer),
@@ -1125,7 +1130,7 @@ func TestClient_VariantFromEnabledFeatureWithNoVariants(t *testing.T) {
 
 	assert.True(variant.FeatureEnabled)
 
-	assert.Equal(disabledVariantFeatureEnabled, variant)
+	assert.Equal(&api.Variant{Name: "disabled", FeatureEnabled: true}, variant)
 
 	variantFromResolver := client.GetVariant(feature, WithVariantContext(context.Context{}), WithVariantResolver(func(featureName string) *api.Feature {
 		if featureName == features[0].Name {
@@ -1140,7 +1145,7 @@ func TestClient_VariantFromEnabledFeatureWithNoVariants(t *testing.T) {
 
 	assert.True(variantFromResolver.FeatureEnabled)
 
-	assert.Equal(disabledVariantFeatureEnabled, variantFromResolver)
+	assert.Equal(&api.Variant{Name: "disabled", FeatureEnabled: true}, variantFromResolver)
 
 	assert.True(gock.IsDone(), "there should be no more mocks")
 }
@@ -1417,3 +1422,459 @@ func TestGetVariant_FallbackVariantFeatureEnabledSettingIsLeftUnchanged(t *testi
 
 	assert.True(gock.IsDone(), "there should be no more mocks")
 }
+
+func TestClient_WithFeatureDefaults(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{})
+
+	defaultVariant := &api.Variant{Name: "declared-variant"}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+		WithFeatureDefaults(map[string]Default{
+			"declared": {Enabled: true, Variant: defaultVariant},
+		}),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.True(client.IsEnabled("declared"))
+	assert.False(client.IsEnabled("declared", WithFallback(false)), "explicit fallback wins over the declared default")
+	assert.False(client.IsEnabled("undeclared"))
+
+	assert.Equal(defaultVariant, client.GetVariant("declared"))
+	assert.Equal(api.GetDefaultVariant(), client.GetVariant("undeclared"))
+
+	client.GetVariant("declared").Name = "modified"
+	assert.Equal("declared-variant", client.GetVariant("declared").Name, "the declared default is not shared with callers")
+
+	err = client.Close()
+	assert.NoError(err)
+}
+
+func TestClient_StrictModeReportsUnknownTogglesOnce(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{})
+
+	warned := make(chan struct{}, 10)
+	mockListener := &MockedListener{}
+	mockListener.On("OnReady").Return()
+	mockListener.On("OnWarning", mock.MatchedBy(func(e error) bool {
+		return strings.Contains(e.Error(), `"typo"`)
+	})).Run(func(args mock.Arguments) { warned <- struct{}{} }).Return()
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(mockListener),
+		WithStrictMode(true),
+		WithFeatureDefaults(map[string]Default{
+			"declared": {Enabled: true},
+		}),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	client.IsEnabled("typo")
+	client.IsEnabled("typo")
+	client.GetVariant("typo")
+	client.IsEnabled("declared")
+
+	select {
+	case <-warned:
+	case <-time.After(time.Second):
+		t.Fatal("expected a warning about the unknown toggle")
+	}
+
+	err = client.Close()
+	assert.NoError(err)
+	mockListener.AssertNumberOfCalls(t, "OnWarning", 1)
+}
+
+func TestClient_Explain(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{
+				{
+					Name:    "explained",
+					Enabled: true,
+					Strategies: []api.Strategy{
+						{Id: 1, Name: "custom"},
+						{Id: 2, Name: "userWithId", Parameters: map[string]interface{}{"userIds": "1"}},
+					},
+				},
+			},
+		})
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	details := client.Explain("explained", WithContext(context.Context{UserId: "1"}))
+	assert.True(details.Found)
+	assert.True(details.Enabled)
+	assert.Equal("disabled", details.Variant.Name)
+	assert.Equal([]string{
+		`strategy "custom" is not implemented by this client`,
+		`strategy "userWithId" is enabled for the context`,
+	}, details.Reasons)
+
+	details = client.Explain("missing", WithFallback(true))
+	assert.False(details.Found)
+	assert.True(details.Enabled)
+	assert.Equal([]string{"feature toggle not found", "using the fallback"}, details.Reasons)
+
+	assert.NoError(client.Close())
+}
+
+func TestClient_WithContextProvider(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{
+				{
+					Name:    "regional",
+					Enabled: true,
+					Strategies: []api.Strategy{
+						{
+							Id:   1,
+							Name: "default",
+							Constraints: []api.Constraint{
+								{ContextName: "region", Operator: api.OperatorIn, Values: []string{"eu"}},
+								{ContextName: "plan", Operator: api.OperatorIn, Values: []string{"pro"}},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+		WithContextProvider(func() context.Context {
+			return context.Context{Environment: "staging", Properties: map[string]string{"region": "us", "plan": "free"}}
+		}),
+		WithContextProvider(func() context.Context {
+			return context.Context{Properties: map[string]string{"region": "eu"}}
+		}),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.Equal(&context.Context{
+		AppName:     mockAppName,
+		Environment: "staging",
+		Properties:  map[string]string{"region": "eu", "plan": "free"},
+	}, client.context(nil), "later providers take precedence over earlier ones")
+
+	assert.False(client.IsEnabled("regional"))
+	assert.True(client.IsEnabled("regional", WithContext(context.Context{Properties: map[string]string{"plan": "pro"}})),
+		"the call context is merged over the provided properties")
+	assert.False(client.IsEnabled("regional", WithContext(context.Context{Properties: map[string]string{"plan": "pro", "region": "us"}})))
+
+	err = client.Close()
+	assert.NoError(err)
+}
+
+func TestClient_WithRefreshedContextProvider(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{})
+
+	var mu sync.Mutex
+	version := "1"
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+		WithRefreshedContextProvider(func() context.Context {
+			mu.Lock()
+			defer mu.Unlock()
+			return context.Context{Properties: map[string]string{"version": version}}
+		}, time.Millisecond),
+	)
+	assert.NoError(err)
+	assert.Equal("1", client.context(nil).Properties["version"])
+
+	mu.Lock()
+	version = "2"
+	mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for client.context(nil).Properties["version"] != "2" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal("2", client.context(nil).Properties["version"])
+
+	err = client.Close()
+	assert.NoError(err)
+}
+
+func TestClient_WithClock(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	launch := api.Feature{
+		Name:    "launch",
+		Enabled: true,
+		Strategies: []api.Strategy{
+			{
+				Id:   1,
+				Name: "default",
+				Constraints: []api.Constraint{
+					{ContextName: "currentTime", Operator: api.OperatorDateAfter, Value: "2022-06-01T00:00:00Z"},
+				},
+			},
+		},
+	}
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{Features: []api.Feature{launch}})
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{Features: []api.Feature{launch, {Name: "refreshed", Enabled: true}}})
+
+	fake := clock.NewFake(time.Date(2022, 5, 31, 0, 0, 0, 0, time.UTC))
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+		WithRefreshInterval(time.Minute),
+		WithClock(fake),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.False(client.IsEnabled("launch"))
+	fake.Advance(48 * time.Hour)
+	assert.True(client.IsEnabled("launch"), "date constraints use the time of the clock")
+
+	deadline := time.Now().Add(time.Second)
+	for !client.IsEnabled("refreshed") && time.Now().Before(deadline) {
+		fake.Advance(time.Minute)
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(client.IsEnabled("refreshed"), "refreshes are scheduled by the clock")
+
+	err = client.Close()
+	assert.NoError(err)
+}
+
+func TestClient_WithConstraintOperator(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{
+				{
+					Name:    "strategy-constraint",
+					Enabled: true,
+					Strategies: []api.Strategy{
+						{
+							Id:          1,
+							Name:        "default",
+							Constraints: []api.Constraint{{ContextName: "userId", Operator: "REGEX_MATCH", Value: "^beta-"}},
+						},
+					},
+				},
+				{
+					Name:    "segment-constraint",
+					Enabled: true,
+					Strategies: []api.Strategy{
+						{Id: 1, Name: "default", Segments: []int{1}},
+					},
+				},
+			},
+			Segments: []api.Segment{
+				{Id: 1, Constraints: []api.Constraint{{ContextName: "userId", Operator: "REGEX_MATCH", Value: "^BETA-", CaseInsensitive: true}}},
+			},
+		})
+
+	regexMatch := func(ctx *context.Context, constraint api.Constraint) (bool, error) {
+		return strings.HasPrefix(ctx.Field(constraint.ContextName), strings.TrimPrefix(constraint.Value, "^")), nil
+	}
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+		WithConstraintOperator("REGEX_MATCH", regexMatch),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	beta := WithContext(context.Context{UserId: "beta-1"})
+	other := WithContext(context.Context{UserId: "user-1"})
+	assert.True(client.IsEnabled("strategy-constraint", beta))
+	assert.False(client.IsEnabled("strategy-constraint", other))
+	assert.True(client.IsEnabled("segment-constraint", beta))
+	assert.False(client.IsEnabled("segment-constraint", other))
+
+	err = client.Close()
+	assert.NoError(err)
+
+	_, err = NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithListener(&NoopListener{}),
+		WithConstraintOperator(api.OperatorIn, regexMatch),
+	)
+	assert.EqualError(err, "constraint operator IN is built in and cannot be replaced")
+}
+
+type errorCollector struct {
+	NoopListener
+	errors chan error
+}
+
+func (l errorCollector) OnError(err error) {
+	l.errors <- err
+}
+
+func TestClient_TypedErrorsAreRateLimited(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{
+				{
+					Name:       "broken",
+					Enabled:    true,
+					Strategies: []api.Strategy{{Id: 7, Name: "default", Segments: []int{3}}},
+				},
+			},
+		})
+
+	fake := clock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	listener := errorCollector{errors: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithClock(fake),
+		WithRefreshInterval(time.Hour),
+		WithErrorInterval(time.Minute),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	for i := 0; i < 3; i++ {
+		assert.False(client.IsEnabled("broken"))
+	}
+	var segmentErr *SegmentNotFoundError
+	if assert.True(errors.As(<-listener.errors, &segmentErr)) {
+		assert.Equal(SegmentNotFoundError{Feature: "broken", StrategyId: 7, SegmentId: 3}, *segmentErr)
+	}
+
+	fake.Advance(time.Minute)
+	assert.False(client.IsEnabled("broken"))
+	assert.True(errors.As(<-listener.errors, &segmentErr), "the error is reported again after the interval")
+
+	assert.NoError(client.Close())
+	assert.Len(listener.errors, 0, "the error is reported once per interval")
+}
+
+func TestClient_ConstraintErrorsAreRateLimitedAcrossValues(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{
+				{
+					Name:    "dated",
+					Enabled: true,
+					Strategies: []api.Strategy{{
+						Id:   1,
+						Name: "default",
+						Constraints: []api.Constraint{{
+							ContextName: "currentTime",
+							Operator:    api.OperatorDateAfter,
+							Value:       "2022-01-01T00:00:00Z",
+						}},
+					}},
+				},
+			},
+		})
+
+	fake := clock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	listener := errorCollector{errors: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithClock(fake),
+		WithRefreshInterval(time.Hour),
+		WithErrorInterval(time.Minute),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	for i := 0; i < 5; i++ {
+		ctx := context.Context{CurrentTime: fmt.Sprintf("not-a-date-%d", i)}
+		assert.False(client.IsEnabled("dated", WithContext(ctx)))
+	}
+	var constraintErr *ConstraintError
+	assert.True(errors.As(<-listener.errors, &constraintErr))
+
+	assert.NoError(client.Close())
+	assert.Len(listener.errors, 0, "errors differing only by the context value are reported once")
+}
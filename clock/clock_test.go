@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	ticker := c.NewTicker(time.Minute)
+	assert.Equal(1, c.Tickers())
+	assert.Equal(start, c.Now())
+
+	c.Advance(30 * time.Second)
+	assert.Len(ticker.C(), 0)
+
+	c.Advance(30 * time.Second)
+	assert.Equal(start.Add(time.Minute), <-ticker.C())
+
+	// Like time.Ticker, ticks are dropped when the receiver is not keeping up.
+	c.Advance(5 * time.Minute)
+	assert.Equal(start.Add(2*time.Minute), <-ticker.C())
+	assert.Len(ticker.C(), 0)
+
+	c.Set(start.Add(7 * time.Minute))
+	assert.Equal(start.Add(7*time.Minute), <-ticker.C())
+
+	ticker.Stop()
+	assert.Equal(0, c.Tickers())
+	c.Advance(time.Hour)
+	assert.Len(ticker.C(), 0)
+}
+
+func TestReal(t *testing.T) {
+	c := Real()
+	ticker := c.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	before := time.Now()
+	tick := <-ticker.C()
+	assert.False(t, tick.Before(before))
+	assert.False(t, c.Now().Before(tick))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
+)
+
+// readFeatures reads an api.FeatureResponse and returns the sorted names of its
+// feature toggles.
+func readFeatures(path string) (api.FeatureResponse, []string, error) {
+	var resp api.FeatureResponse
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return resp, nil, err
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, nil, err
+	}
+	names := make([]string, 0, len(resp.Features))
+	for _, f := range resp.Features {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return resp, names, nil
+}
+
+// readContextFile reads a single context from a JSON file.
+func readContextFile(path string) (context.Context, error) {
+	var ctx context.Context
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ctx, err
+	}
+	err = json.Unmarshal(data, &ctx)
+	return ctx, err
+}
+
+// readContextsCSV reads one context per row. The header names the context
+// fields, any column that is not a known field is used as a property.
+func readContextsCSV(r io.Reader) ([]context.Context, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+
+	header := records[0]
+	contexts := make([]context.Context, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var ctx context.Context
+		for i, value := range record {
+			setField(&ctx, header[i], value)
+		}
+		contexts = append(contexts, ctx)
+	}
+	return contexts, nil
+}
+
+func setField(ctx *context.Context, name, value string) {
+	switch name {
+	case "userId":
+		ctx.UserId = value
+	case "sessionId":
+		ctx.SessionId = value
+	case "remoteAddress":
+		ctx.RemoteAddress = value
+	case "environment":
+		ctx.Environment = value
+	case "appName":
+		ctx.AppName = value
+	case "currentTime":
+		ctx.CurrentTime = value
+	default:
+		if value == "" {
+			return
+		}
+		if ctx.Properties == nil {
+			ctx.Properties = map[string]string{}
+		}
+		ctx.Properties[name] = value
+	}
+}
+
+// properties collects repeated -property key=value flags.
+type properties map[string]string
+
+func (p properties) String() string {
+	pairs := make([]string, 0, len(p))
+	for k, v := range p {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p properties) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	p[k] = v
+	return nil
+}
+
+// evaluation is a single line of output.
+type evaluation struct {
+	Context int          `json:"context"`
+	Feature string       `json:"feature"`
+	Found   bool         `json:"found"`
+	Enabled bool         `json:"enabled"`
+	Variant *api.Variant `json:"variant"`
+	Reasons []string     `json:"reasons"`
+	Errors  []string     `json:"errors,omitempty"`
+}
+
+// evaluate evaluates every feature for every context, each context being layered
+// over the static context like the client does.
+func evaluate(e *evaluator.Evaluator, static context.Context, features []string, contexts []context.Context) []evaluation {
+	var evaluations []evaluation
+	for i, ctx := range contexts {
+		for _, feature := range features {
+			result := e.Variant(feature, static.Override(ctx), evaluator.WithReasons())
+			ev := evaluation{
+				Context: i,
+				Feature: feature,
+				Found:   result.Feature != nil,
+				Enabled: result.Enabled,
+				Variant: result.ResolveVariant(nil),
+				Reasons: result.Reasons,
+			}
+			for _, err := range append(result.Errors, result.Warnings...) {
+				ev.Errors = append(ev.Errors, err.Error())
+			}
+			evaluations = append(evaluations, ev)
+		}
+	}
+	return evaluations
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	assert := assert.New(t)
+
+	resp, names, err := readFeatures("testdata/features.json")
+	assert.NoError(err)
+	assert.Equal([]string{"beta", "layout", "off"}, names)
+
+	file, err := os.Open("testdata/contexts.csv")
+	assert.NoError(err)
+	defer file.Close()
+	contexts, err := readContextsCSV(file)
+	assert.NoError(err)
+	assert.Len(contexts, 2)
+	assert.Equal("pro", contexts[0].Properties["plan"])
+
+	static := context.Context{AppName: "unleash-eval", Environment: "default"}
+	evaluations := evaluate(evaluator.New(resp), static, names, contexts)
+	assert.Len(evaluations, 6)
+
+	results := map[int]map[string]bool{0: {}, 1: {}}
+	for _, e := range evaluations {
+		results[e.Context][e.Feature] = e.Enabled
+		assert.NotEmpty(e.Reasons)
+		if e.Feature == "layout" {
+			assert.Equal("wide", e.Variant.Name)
+		}
+	}
+	assert.Equal(map[string]bool{"beta": true, "layout": true, "off": false}, results[0])
+	assert.Equal(map[string]bool{"beta": false, "layout": true, "off": false}, results[1])
+
+	assert.Equal([]string{`constraints of strategy "default" are not satisfied`, "no strategy is enabled for the context"}, evaluations[3].Reasons)
+
+	// beta is enabled for the first context without variants, off is disabled.
+	assert.Equal("beta", evaluations[0].Feature)
+	assert.Equal(&api.Variant{Name: "disabled", FeatureEnabled: true}, evaluations[0].Variant)
+	assert.Equal("off", evaluations[2].Feature)
+	assert.Equal(&api.Variant{Name: "disabled", FeatureEnabled: false}, evaluations[2].Variant)
+	assert.Equal(&api.Variant{Name: "disabled"}, api.GetDefaultVariant(), "the shared default variant is not modified")
+}
@@ -0,0 +1,104 @@
+// Command unleash-eval evaluates feature toggles offline from a feature response,
+// using the same evaluator as the client.
+//
+// Usage:
+//
+//	unleash-eval -features features.json [-feature name] [context flags] [-json]
+//
+// The features file is an /client/features response including its segments. The
+// context is built from a JSON file given with -context, overridden by the
+// -user-id, -session-id, -remote-address, -environment, -app-name, -current-time
+// and repeated -property key=value flags. Alternatively -contexts evaluates every
+// row of a CSV file whose header names the context fields, unknown columns being
+// used as properties. Without -feature every toggle of the file is evaluated.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
+)
+
+func main() {
+	featuresPath := flag.String("features", "", "path to a /client/features response")
+	feature := flag.String("feature", "", "evaluate a single feature toggle instead of all of them")
+	contextPath := flag.String("context", "", "path to a JSON file holding the context")
+	contextsPath := flag.String("contexts", "", "path to a CSV file holding one context per row")
+	appName := flag.String("app-name", "unleash-eval", "application name of the context")
+	environment := flag.String("environment", "default", "environment of the context")
+	asJSON := flag.Bool("json", false, "print one JSON object per evaluation")
+
+	var ctx context.Context
+	props := properties{}
+	flag.StringVar(&ctx.UserId, "user-id", "", "user id of the context")
+	flag.StringVar(&ctx.SessionId, "session-id", "", "session id of the context")
+	flag.StringVar(&ctx.RemoteAddress, "remote-address", "", "remote address of the context")
+	flag.StringVar(&ctx.CurrentTime, "current-time", "", "current time of the context, in RFC3339")
+	flag.Var(props, "property", "property of the context as key=value, may be repeated")
+	flag.Parse()
+
+	if *featuresPath == "" {
+		fail(2, "-features is required")
+	}
+
+	resp, names, err := readFeatures(*featuresPath)
+	if err != nil {
+		fail(2, "loading %s: %v", *featuresPath, err)
+	}
+	if *feature != "" {
+		names = []string{*feature}
+	}
+
+	var contexts []context.Context
+	if *contextsPath != "" {
+		file, err := os.Open(*contextsPath)
+		if err != nil {
+			fail(2, "%v", err)
+		}
+		contexts, err = readContextsCSV(file)
+		file.Close()
+		if err != nil {
+			fail(2, "loading %s: %v", *contextsPath, err)
+		}
+	} else {
+		base := context.Context{}
+		if *contextPath != "" {
+			if base, err = readContextFile(*contextPath); err != nil {
+				fail(2, "loading %s: %v", *contextPath, err)
+			}
+		}
+		if len(props) > 0 {
+			ctx.Properties = props
+		}
+		contexts = []context.Context{*base.Override(ctx)}
+	}
+
+	static := context.Context{AppName: *appName, Environment: *environment}
+	evaluations := evaluate(evaluator.New(resp), static, names, contexts)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range evaluations {
+			enc.Encode(e)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tFEATURE\tENABLED\tVARIANT\tREASONS")
+	for _, e := range evaluations {
+		fmt.Fprintf(w, "%d\t%s\t%v\t%s\t%s\n", e.Context, e.Feature, e.Enabled, e.Variant.Name, strings.Join(append(e.Reasons, e.Errors...), "; "))
+	}
+	w.Flush()
+}
+
+func fail(code int, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "unleash-eval: "+format+"\n", args...)
+	os.Exit(code)
+}
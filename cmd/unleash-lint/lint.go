@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+)
+
+// evaluationFuncs are the functions whose first argument is the name of a
+// feature toggle.
+var evaluationFuncs = map[string]bool{
+	"IsEnabled":   true,
+	"GetVariant":  true,
+	"BoolFlag":    true,
+	"VariantFlag": true,
+}
+
+// expectedLifetimes is the time after which a toggle of the given type is
+// considered potentially stale. Types missing from the map are permanent.
+var expectedLifetimes = map[string]time.Duration{
+	"release":     40 * 24 * time.Hour,
+	"experiment":  40 * 24 * time.Hour,
+	"operational": 7 * 24 * time.Hour,
+}
+
+// reference is a call to an evaluation function with a literal feature name.
+type reference struct {
+	Name string
+	Pos  token.Position
+}
+
+// finding is a single problem reported by the linter.
+type finding struct {
+	Pos     string
+	Message string
+}
+
+func (f finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+// scan parses the Go files matched by the patterns and returns all references to
+// feature toggles. A pattern is a directory, optionally followed by /... to
+// include its subdirectories.
+func scan(patterns []string, includeTests bool) ([]reference, error) {
+	fset := token.NewFileSet()
+	var refs []reference
+
+	for _, pattern := range patterns {
+		dir, recursive := strings.TrimSuffix(pattern, "..."), strings.HasSuffix(pattern, "...")
+		// Clean turns ./ and the empty directory of ... into ., so that the root
+		// is recognized below and not skipped as a hidden directory.
+		dir = filepath.Clean(dir)
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path == dir {
+					return nil
+				}
+				name := info.Name()
+				if !recursive || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") || (!includeTests && strings.HasSuffix(path, "_test.go")) {
+				return nil
+			}
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			refs = append(refs, fileReferences(fset, file)...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+func fileReferences(fset *token.FileSet, file *ast.File) []reference {
+	var refs []reference
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || !evaluationFuncs[funcName(call.Fun)] {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		if name, err := strconv.Unquote(lit.Value); err == nil {
+			refs = append(refs, reference{Name: name, Pos: fset.Position(lit.Pos())})
+		}
+		return true
+	})
+	return refs
+}
+
+// funcName returns the name of the called function, ignoring the package or
+// receiver it is selected from and any type arguments.
+func funcName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return funcName(e.X)
+	case *ast.IndexListExpr:
+		return funcName(e.X)
+	}
+	return ""
+}
+
+// loadFeatures reads either a /client/features response (as used for
+// bootstrapping) or a backup file written by the default storage. The format is
+// told by its shape: a response has a version and an array of features, while a
+// backup is an object keyed by feature toggle names, one of which may well be
+// "features".
+func loadFeatures(path string) (map[string]api.Feature, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	features := map[string]api.Feature{}
+	if isFeatureResponse(fields) {
+		var resp api.FeatureResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Features {
+			features[f.Name] = f
+		}
+		return features, nil
+	}
+
+	if err := json.Unmarshal(data, &features); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// isFeatureResponse reports whether the fields are those of a /client/features
+// response.
+func isFeatureResponse(fields map[string]json.RawMessage) bool {
+	list, ok := fields["features"]
+	if !ok {
+		return false
+	}
+	if _, ok := fields["version"]; !ok {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(list)), "[")
+}
+
+// lint compares the references found in code with the features defined upstream.
+func lint(refs []reference, features map[string]api.Feature, upstream string, now time.Time) []finding {
+	var findings []finding
+	referenced := map[string]bool{}
+
+	for _, ref := range refs {
+		referenced[ref.Name] = true
+		pos := ref.Pos.String()
+
+		f, ok := features[ref.Name]
+		if !ok {
+			findings = append(findings, finding{pos, fmt.Sprintf("feature toggle %q is not defined upstream", ref.Name)})
+			continue
+		}
+		lifetime, ok := expectedLifetimes[f.Type]
+		if ok && !f.CreatedAt.IsZero() && now.Sub(f.CreatedAt) > lifetime {
+			findings = append(findings, finding{pos, fmt.Sprintf(
+				"feature toggle %q is a %s toggle created on %s, older than its expected lifetime of %d days, consider cleaning it up",
+				ref.Name, f.Type, f.CreatedAt.Format("2006-01-02"), int(lifetime.Hours()/24),
+			)})
+		}
+	}
+
+	var unused []string
+	for name := range features {
+		if !referenced[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		findings = append(findings, finding{upstream, fmt.Sprintf("feature toggle %q is never referenced in code", name)})
+	}
+
+	return findings
+}
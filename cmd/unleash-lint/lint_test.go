@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	assert := assert.New(t)
+
+	refs, err := scan([]string{"testdata/src/..."}, true)
+	assert.NoError(err)
+
+	names := []string{}
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	assert.Equal([]string{"layout", "checkout", "old-release", "typo"}, names)
+
+	features, err := loadFeatures("testdata/features.json")
+	assert.NoError(err)
+
+	now := time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)
+	messages := []string{}
+	for _, f := range lint(refs, features, "features.json", now) {
+		messages = append(messages, f.Message)
+	}
+	assert.Equal([]string{
+		`feature toggle "old-release" is a release toggle created on 2023-01-01, older than its expected lifetime of 40 days, consider cleaning it up`,
+		`feature toggle "typo" is not defined upstream`,
+		`feature toggle "unused" is never referenced in code`,
+	}, messages)
+}
+
+func TestLoadFeatures_BackupWithFeaturesToggle(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "backup.json")
+	assert.NoError(os.WriteFile(path, []byte(`{
+		"features": {"name": "features", "type": "release", "enabled": true},
+		"version": {"name": "version", "type": "experiment", "enabled": false}
+	}`), 0644))
+
+	features, err := loadFeatures(path)
+	assert.NoError(err)
+	assert.Len(features, 2)
+	assert.Equal("release", features["features"].Type)
+	assert.Equal("experiment", features["version"].Type)
+}
+
+func TestScan_DefaultPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	wd, err := os.Getwd()
+	assert.NoError(err)
+	assert.NoError(os.Chdir("testdata/src"))
+	defer os.Chdir(wd)
+
+	for _, pattern := range []string{"./...", "...", "."} {
+		refs, err := scan([]string{pattern}, true)
+		assert.NoError(err)
+
+		names := []string{}
+		for _, ref := range refs {
+			names = append(names, ref.Name)
+		}
+		if pattern == "." {
+			assert.Empty(names, "subdirectories are only scanned with /...")
+		} else {
+			assert.Equal([]string{"layout", "checkout", "old-release", "typo"}, names, pattern)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Command unleash-lint statically finds the feature toggles referenced by Go code
+// and compares them with the toggles defined upstream.
+//
+// It looks for calls to IsEnabled, GetVariant, BoolFlag and VariantFlag whose first
+// argument is a string literal and reports toggles used in code but missing
+// upstream, toggles defined upstream but never referenced, and references to
+// toggles that have outlived the expected lifetime of their type.
+//
+// Usage:
+//
+//	unleash-lint -features features.json [-tests=false] [packages]
+//
+// The features file is either a /client/features response, as used for
+// bootstrapping, or a backup file written by the client. Packages are
+// directories, optionally followed by /... to include subdirectories, and
+// default to ./... . The exit status is 1 if anything was reported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	featuresPath := flag.String("features", "", "path to a feature snapshot or bootstrap JSON file")
+	includeTests := flag.Bool("tests", true, "also scan _test.go files")
+	flag.Parse()
+
+	if *featuresPath == "" {
+		fmt.Fprintln(os.Stderr, "unleash-lint: -features is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	features, err := loadFeatures(*featuresPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unleash-lint: loading %s: %v\n", *featuresPath, err)
+		os.Exit(2)
+	}
+
+	refs, err := scan(patterns, *includeTests)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unleash-lint: %v\n", err)
+		os.Exit(2)
+	}
+
+	findings := lint(refs, features, *featuresPath, time.Now())
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"github.com/Unleash/unleash-client-go/v4"
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+type layout int
+
+var Layout = unleash.VariantFlag[layout]("layout", map[string]layout{"wide": 1}, 0)
+
+func handle(client *unleash.Client, ctx context.Context) {
+	if client.IsEnabled("checkout") {
+		client.GetVariant("old-release")
+	}
+	unleash.IsEnabled("typo")
+	name := "dynamic"
+	client.IsEnabled(name)
+}
@@ -1,12 +1,15 @@
 package unleash
 
 import (
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
 	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
 	"github.com/Unleash/unleash-client-go/v4/strategy"
 )
 
@@ -16,6 +19,9 @@ type configOption struct {
 	instanceId      string
 	url             string
 	projectName     string
+	projects        []string
+	namePrefix      string
+	tags            []string
 	refreshInterval time.Duration
 	metricsInterval time.Duration
 	disableMetrics  bool
@@ -25,11 +31,44 @@ type configOption struct {
 	storage         Storage
 	httpClient      *http.Client
 	customHeaders   http.Header
+	featureDefaults map[string]Default
+	strictMode      bool
+	providers       []contextProvider
+	clock           clock.Clock
+	operators       map[api.Operator]evaluator.ConstraintOperator
+	errorInterval   time.Duration
+	eventBufferSize int
+	overflowPolicy  OverflowPolicy
+	logger          *slog.Logger
+	maxDataAge      time.Duration
+	stalePolicy     StalePolicy
+	staleTypes      []string
+
+	sharedRepository *Repository
 }
 
 // ConfigOption represents a option for configuring the client.
 type ConfigOption func(*configOption)
 
+// WithEventBufferSize sets the capacity of the queues holding the errors,
+// warnings, counts and sent metrics until they are delivered to the listener or
+// read from the channels of the client. Defaults to 100.
+func WithEventBufferSize(size int) ConfigOption {
+	return func(o *configOption) {
+		o.eventBufferSize = size
+	}
+}
+
+// WithEventOverflowPolicy sets what happens to events emitted while their queue
+// is full. With the default OverflowDropOldest, and with OverflowDropNewest,
+// evaluations never block on event delivery and the discarded events are counted
+// in Client.DroppedEvents.
+func WithEventOverflowPolicy(policy OverflowPolicy) ConfigOption {
+	return func(o *configOption) {
+		o.overflowPolicy = policy
+	}
+}
+
 // WithListener allows users to register a type that implements one or more of
 // the listener interfaces. If no listener is registered then the user is responsible
 // for draining the various channels on the client. Failure to do so will stop the client
@@ -139,6 +178,157 @@ func WithProjectName(projectName string) ConfigOption {
 	}
 }
 
+// WithProjects restricts the feature toggles fetched from the server to those of
+// the given projects. It can be combined with WithProjectName.
+func WithProjects(projects ...string) ConfigOption {
+	return func(o *configOption) {
+		o.projects = append(o.projects, projects...)
+	}
+}
+
+// WithNamePrefix restricts the feature toggles fetched from the server to those
+// whose name starts with the prefix.
+func WithNamePrefix(prefix string) ConfigOption {
+	return func(o *configOption) {
+		o.namePrefix = prefix
+	}
+}
+
+// WithTags restricts the feature toggles fetched from the server to those with
+// any of the given tags, formatted as type:value, for instance "simple:web".
+func WithTags(tags ...string) ConfigOption {
+	return func(o *configOption) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// Default declares the value a feature toggle should resolve to when it is not
+// known to the repository.
+type Default struct {
+	// Enabled is the value returned by IsEnabled.
+	Enabled bool
+
+	// Variant is the variant returned by GetVariant. If nil, the default
+	// disabled variant is returned.
+	Variant *api.Variant
+}
+
+// WithFeatureDefaults declares the defaults of feature toggles in a single place.
+// A declared default is used whenever the toggle is missing from the repository
+// and no fallback was passed to IsEnabled or GetVariant.
+func WithFeatureDefaults(defaults map[string]Default) ConfigOption {
+	return func(o *configOption) {
+		o.featureDefaults = defaults
+	}
+}
+
+// WithStrictMode makes the client emit a warning, once per name, whenever a
+// feature toggle is evaluated that is neither in the repository nor declared with
+// WithFeatureDefaults. Toggles are only reported once the client is ready.
+func WithStrictMode(strict bool) ConfigOption {
+	return func(o *configOption) {
+		o.strictMode = strict
+	}
+}
+
+// WithContextProvider registers a provider enriching the context of every
+// evaluation. The provider is called once when the client is created. Providers
+// are layered over the environment and application name of the client in the
+// order they are registered, and beneath the context passed to IsEnabled or
+// GetVariant. Properties are merged between the layers.
+func WithContextProvider(provider ContextProvider) ConfigOption {
+	return WithRefreshedContextProvider(provider, 0)
+}
+
+// WithRefreshedContextProvider is like WithContextProvider, but the provider is
+// called again at the given interval for as long as the client is open.
+func WithRefreshedContextProvider(provider ContextProvider, refreshInterval time.Duration) ConfigOption {
+	return func(o *configOption) {
+		o.providers = append(o.providers, contextProvider{provide: provider, refreshInterval: refreshInterval})
+	}
+}
+
+// WithClock sets the clock used for date constraints, metrics buckets, the
+// scheduling of refreshes, metrics and context providers, and to seed random
+// stickiness. It allows controlling time-dependent behavior in tests with a
+// clock.Fake. Defaults to the system clock.
+func WithClock(c clock.Clock) ConfigOption {
+	return func(o *configOption) {
+		o.clock = c
+	}
+}
+
+// WithConstraintOperator registers a custom constraint operator, such as
+// REGEX_MATCH or IP_IN_CIDR, used by both strategy and segment constraints. The
+// client inverts the result for Inverted constraints and lower-cases the values
+// of CaseInsensitive constraints before calling fn. NewClient fails if the
+// operator is one of the built-in operators.
+func WithConstraintOperator(operator api.Operator, fn evaluator.ConstraintOperator) ConfigOption {
+	return func(o *configOption) {
+		if o.operators == nil {
+			o.operators = make(map[api.Operator]evaluator.ConstraintOperator)
+		}
+		o.operators[operator] = fn
+	}
+}
+
+// WithErrorInterval sets the minimum interval between two reports of the same
+// error or warning raised while evaluating feature toggles, such as a
+// SegmentNotFoundError. Repeated reports within the interval are dropped, so that
+// a broken toggle evaluated in a hot path does not flood the listener. A zero
+// interval reports every occurrence. Defaults to one minute.
+func WithErrorInterval(interval time.Duration) ConfigOption {
+	return func(o *configOption) {
+		o.errorInterval = interval
+	}
+}
+
+// WithLogger sets the logger receiving the diagnostics of the client, such as
+// fetch errors and invalid feature toggles, with structured attributes. By
+// default nothing is logged.
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return func(o *configOption) {
+		o.logger = logger
+	}
+}
+
+// WithMaxDataAge sets the maximum age of the feature toggles, measured from the
+// last successful fetch, or from the time the backup was written when the
+// toggles come from a backup. Toggles from a bootstrap, or from a custom storage,
+// are of unknown age and stale until a fetch succeeds. Once the toggles are
+// older, a StaleDataError is reported as a warning and the stale policy applies
+// until a fetch succeeds. The age is checked at every refresh interval. By
+// default there is no maximum age.
+func WithMaxDataAge(maxAge time.Duration) ConfigOption {
+	return func(o *configOption) {
+		o.maxDataAge = maxAge
+	}
+}
+
+// WithStalePolicy sets how feature toggles are evaluated while they are older
+// than the maximum age set with WithMaxDataAge. If toggle types are given, such
+// as "kill-switch" or "operational", the policy only applies to the feature
+// toggles of those types and the others keep being served. Defaults to
+// StaleKeepServing.
+func WithStalePolicy(policy StalePolicy, toggleTypes ...string) ConfigOption {
+	return func(o *configOption) {
+		o.stalePolicy = policy
+		o.staleTypes = toggleTypes
+	}
+}
+
+// WithSharedRepository makes the client use a repository created with
+// NewRepository instead of fetching the feature toggles itself. The options of
+// the client that relate to fetching and storing the feature toggles are then
+// ignored, except that the URL, app name, HTTP client and custom headers of the
+// repository are used for the metrics of the client when they are not set. The
+// client holds a reference to the repository until it is closed.
+func WithSharedRepository(repo *Repository) ConfigOption {
+	return func(o *configOption) {
+		o.sharedRepository = repo
+	}
+}
+
 // FeatureResolver represents a function to be called to resolve the feature instead of using the repository
 type FeatureResolver func(feature string) *api.Feature
 
@@ -157,6 +347,18 @@ type featureOption struct {
 	fallbackFunc FallbackFunc
 	ctx          *context.Context
 	resolver     FeatureResolver
+	reasons      *[]string
+
+	// fromHandler is set for the evaluations made from an event handler, whose
+	// reports must not wait for the goroutine running the handler.
+	fromHandler bool
+}
+
+// explain records a step of the evaluation if the reasons were requested.
+func (o featureOption) explain(reason string) {
+	if o.reasons != nil {
+		*o.reasons = append(*o.reasons, reason)
+	}
 }
 
 // FeatureOption provides options for querying if a feature is enabled or not.
@@ -247,13 +449,18 @@ func WithVariantFallbackFunc(variantFallbackFunc VariantFallbackFunc) VariantOpt
 type repositoryOptions struct {
 	appName         string
 	instanceId      string
-	projectName     string
+	projects        []string
+	namePrefix      string
+	tags            []string
 	url             url.URL
 	backupPath      string
 	refreshInterval time.Duration
 	storage         Storage
 	httpClient      *http.Client
 	customHeaders   http.Header
+	clock           clock.Clock
+	maxDataAge      time.Duration
+	logger          *slog.Logger
 }
 
 type metricsOptions struct {
@@ -265,4 +472,5 @@ type metricsOptions struct {
 	disableMetrics  bool
 	httpClient      *http.Client
 	customHeaders   http.Header
+	clock           clock.Clock
 }
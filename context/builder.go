@@ -0,0 +1,126 @@
+package context
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// reservedFields are the names resolved by Field before the properties are
+// searched, which makes properties with those names unreachable.
+var reservedFields = map[string]bool{
+	"userId":        true,
+	"sessionId":     true,
+	"remoteAddress": true,
+	"environment":   true,
+	"appName":       true,
+	"currentTime":   true,
+}
+
+// Builder builds a Context with typed setters. A Builder is immutable: every
+// setter returns a new Builder and leaves the receiver untouched, so a partially
+// configured Builder can be shared between goroutines and extended concurrently.
+// The zero value is ready to use.
+type Builder struct {
+	ctx Context
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() Builder {
+	return Builder{}
+}
+
+// WithUserId sets the id of the user.
+func (b Builder) WithUserId(userId string) Builder {
+	b.ctx.UserId = userId
+	return b
+}
+
+// WithSessionId sets the id of the session.
+func (b Builder) WithSessionId(sessionId string) Builder {
+	b.ctx.SessionId = sessionId
+	return b
+}
+
+// WithRemoteAddress sets the IP address of the machine.
+func (b Builder) WithRemoteAddress(address string) Builder {
+	b.ctx.RemoteAddress = address
+	return b
+}
+
+// WithEnvironment sets the environment the application is running in.
+func (b Builder) WithEnvironment(environment string) Builder {
+	b.ctx.Environment = environment
+	return b
+}
+
+// WithAppName sets the application name.
+func (b Builder) WithAppName(appName string) Builder {
+	b.ctx.AppName = appName
+	return b
+}
+
+// WithCurrentTime sets the time used for date constraints.
+func (b Builder) WithCurrentTime(t time.Time) Builder {
+	b.ctx.CurrentTime = t.UTC().Format(time.RFC3339Nano)
+	return b
+}
+
+// WithProperty sets an additional property.
+func (b Builder) WithProperty(name, value string) Builder {
+	props := make(map[string]string, len(b.ctx.Properties)+1)
+	for k, v := range b.ctx.Properties {
+		props[k] = v
+	}
+	props[name] = value
+	b.ctx.Properties = props
+	return b
+}
+
+// WithProperties sets several additional properties at once.
+func (b Builder) WithProperties(properties map[string]string) Builder {
+	props := make(map[string]string, len(b.ctx.Properties)+len(properties))
+	for k, v := range b.ctx.Properties {
+		props[k] = v
+	}
+	for k, v := range properties {
+		props[k] = v
+	}
+	b.ctx.Properties = props
+	return b
+}
+
+// Build validates the configured values and returns the Context. The returned
+// Context does not share any state with the Builder.
+func (b Builder) Build() (Context, error) {
+	if b.ctx.RemoteAddress != "" && net.ParseIP(b.ctx.RemoteAddress) == nil {
+		return Context{}, fmt.Errorf("remote address %q is not a valid IP address", b.ctx.RemoteAddress)
+	}
+	for name := range b.ctx.Properties {
+		if name == "" {
+			return Context{}, fmt.Errorf("property name must not be empty")
+		}
+		if reservedFields[name] {
+			return Context{}, fmt.Errorf("property %q is shadowed by the context field of the same name", name)
+		}
+	}
+
+	ctx := b.ctx
+	if b.ctx.Properties != nil {
+		ctx.Properties = make(map[string]string, len(b.ctx.Properties))
+		for k, v := range b.ctx.Properties {
+			ctx.Properties[k] = v
+		}
+	}
+	return ctx, nil
+}
+
+// MustBuild is like Build but panics if the values are invalid. It simplifies
+// the initialization of contexts from constant values.
+func (b Builder) MustBuild() Context {
+	ctx, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return ctx
+}
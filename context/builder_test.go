@@ -0,0 +1,61 @@
+package context
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2022, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+	ctx, err := NewBuilder().
+		WithUserId("123").
+		WithSessionId("abc").
+		WithRemoteAddress("10.0.0.1").
+		WithCurrentTime(now).
+		WithProperty("plan", "pro").
+		Build()
+
+	assert.NoError(err)
+	assert.Equal(Context{
+		UserId:        "123",
+		SessionId:     "abc",
+		RemoteAddress: "10.0.0.1",
+		CurrentTime:   "2022-03-04T04:06:07Z",
+		Properties:    map[string]string{"plan": "pro"},
+	}, ctx)
+}
+
+func TestBuilder_Immutable(t *testing.T) {
+	assert := assert.New(t)
+
+	base := NewBuilder().WithProperty("plan", "pro")
+	a := base.WithProperty("region", "eu").MustBuild()
+	b := base.WithProperty("region", "us").MustBuild()
+
+	assert.Equal(map[string]string{"plan": "pro", "region": "eu"}, a.Properties)
+	assert.Equal(map[string]string{"plan": "pro", "region": "us"}, b.Properties)
+	assert.Equal(map[string]string{"plan": "pro"}, base.MustBuild().Properties)
+
+	built := base.MustBuild()
+	built.Properties["plan"] = "free"
+	assert.Equal("pro", base.MustBuild().Properties["plan"])
+}
+
+func TestBuilder_Validation(t *testing.T) {
+	_, err := NewBuilder().WithRemoteAddress("not an ip").Build()
+	assert.Error(t, err)
+
+	_, err = NewBuilder().WithProperty("", "value").Build()
+	assert.Error(t, err)
+
+	_, err = NewBuilder().WithProperty("userId", "123").Build()
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		NewBuilder().WithProperty("", "value").MustBuild()
+	})
+}
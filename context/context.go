@@ -25,9 +25,32 @@ type Context struct {
 	Properties map[string]string
 }
 
+// OverrideOption customizes how Override combines two contexts.
+type OverrideOption func(*overrideOptions)
+
+type overrideOptions struct {
+	mergeProperties bool
+}
+
+// MergeProperties makes Override merge the properties of both contexts rather than
+// replacing them wholesale. Properties of 'src' win when both contexts set the same
+// name. The merged properties are a new map so neither context is modified.
+func MergeProperties() OverrideOption {
+	return func(o *overrideOptions) {
+		o.mergeProperties = true
+	}
+}
+
 // Override will take all non-empty values in 'src' and replace the
-// corresponding values in this context with those.
-func (ctx Context) Override(src Context) *Context {
+// corresponding values in this context with those. By default a non-nil
+// Properties map in 'src' replaces the properties of this context, unless the
+// MergeProperties option is given.
+func (ctx Context) Override(src Context, opts ...OverrideOption) *Context {
+	var options overrideOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if src.UserId != "" {
 		ctx.UserId = src.UserId
 	}
@@ -46,7 +69,18 @@ func (ctx Context) Override(src Context) *Context {
 	if src.CurrentTime != "" {
 		ctx.CurrentTime = src.CurrentTime
 	}
-	if src.Properties != nil {
+	if options.mergeProperties {
+		if len(src.Properties) > 0 {
+			props := make(map[string]string, len(ctx.Properties)+len(src.Properties))
+			for k, v := range ctx.Properties {
+				props[k] = v
+			}
+			for k, v := range src.Properties {
+				props[k] = v
+			}
+			ctx.Properties = props
+		}
+	} else if src.Properties != nil {
 		ctx.Properties = src.Properties
 	}
 
@@ -64,3 +64,30 @@ func TestContext_Field(t *testing.T) {
 	assert.Equal(t, ctx.AppName, ctx.Field(ctx.AppName))
 	assert.Equal(t, "Bar", ctx.Field("Foo"))
 }
+
+func TestOverride_MergeProperties(t *testing.T) {
+	static := Context{
+		AppName:    "testApp",
+		Properties: map[string]string{"region": "eu", "plan": "free"},
+	}
+
+	actual := static.Override(Context{
+		UserId:     "123",
+		Properties: map[string]string{"plan": "pro"},
+	}, MergeProperties())
+
+	assert.Equal(t, &Context{
+		UserId:     "123",
+		AppName:    "testApp",
+		Properties: map[string]string{"region": "eu", "plan": "pro"},
+	}, actual)
+	assert.Equal(t, "free", static.Properties["plan"], "the receiver must not be modified")
+
+	replaced := static.Override(Context{Properties: map[string]string{"plan": "pro"}})
+	assert.Equal(t, map[string]string{"plan": "pro"}, replaced.Properties)
+
+	src := Context{Properties: map[string]string{"plan": "pro"}}
+	merged := Context{}.Override(src, MergeProperties())
+	merged.Properties["plan"] = "free"
+	assert.Equal(t, "pro", src.Properties["plan"], "the source must not be modified")
+}
@@ -0,0 +1,243 @@
+package context
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// structField describes how a field of a struct is copied to a Context.
+type structField struct {
+	index     []int
+	name      string
+	property  bool
+	omitEmpty bool
+	format    func(v reflect.Value) (string, bool)
+}
+
+// structInfos caches the fields of the types passed to FromStruct.
+var structInfos sync.Map // map[reflect.Type][]structField
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// FromStruct builds a Context from the fields of a struct, or of a pointer to a
+// struct, tagged with "unleash". The tag names either a field of the context or a
+// property:
+//
+//	type User struct {
+//		ID        int64     `unleash:"userId"`
+//		Plan      string    `unleash:"prop=plan"`
+//		SignedUp  time.Time `unleash:"prop=signedUp,omitempty"`
+//		AppVer    Version   `unleash:"prop=appVersion"`
+//	}
+//
+// Strings, booleans and numbers are formatted with strconv, times in RFC3339 as
+// expected by date constraints, and other types through their String or
+// MarshalText method, which is how version types are usually rendered for semver
+// constraints. Nil pointers are skipped, and so are zero values of fields with the
+// omitempty option. Untagged exported embedded structs are searched for tagged
+// fields.
+//
+// The metadata of a type is computed once and cached, so FromStruct is cheap
+// enough to be called for every evaluation.
+func FromStruct(v interface{}) (Context, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return Context{}, fmt.Errorf("unleash: FromStruct of nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return Context{}, fmt.Errorf("unleash: FromStruct of non-struct type %T", v)
+	}
+
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return Context{}, err
+	}
+
+	var ctx Context
+	for _, f := range fields {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		value, ok := f.format(fv)
+		if !ok {
+			continue
+		}
+		if f.property {
+			if ctx.Properties == nil {
+				ctx.Properties = make(map[string]string)
+			}
+			ctx.Properties[f.name] = value
+			continue
+		}
+		switch f.name {
+		case "userId":
+			ctx.UserId = value
+		case "sessionId":
+			ctx.SessionId = value
+		case "remoteAddress":
+			ctx.RemoteAddress = value
+		case "environment":
+			ctx.Environment = value
+		case "appName":
+			ctx.AppName = value
+		case "currentTime":
+			ctx.CurrentTime = value
+		}
+	}
+	return ctx, nil
+}
+
+func structFields(t reflect.Type) ([]structField, error) {
+	if cached, ok := structInfos.Load(t); ok {
+		return cached.([]structField), nil
+	}
+	fields, err := parseStructFields(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	structInfos.Store(t, fields)
+	return fields, nil
+}
+
+func parseStructFields(t reflect.Type, index []int) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		tag, tagged := sf.Tag.Lookup("unleash")
+		if !tagged {
+			if sf.Anonymous && sf.IsExported() && sf.Type.Kind() == reflect.Struct {
+				embedded, err := parseStructFields(sf.Type, fieldIndex)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embedded...)
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("unleash: tagged field %s.%s is not exported", t, sf.Name)
+		}
+
+		f := structField{index: fieldIndex}
+		parts := strings.Split(tag, ",")
+		for _, opt := range parts[1:] {
+			if opt != "omitempty" {
+				return nil, fmt.Errorf("unleash: unknown option %q on field %s.%s", opt, t, sf.Name)
+			}
+			f.omitEmpty = true
+		}
+		if prop := strings.TrimPrefix(parts[0], "prop="); prop != parts[0] {
+			if prop == "" {
+				return nil, fmt.Errorf("unleash: empty property name on field %s.%s", t, sf.Name)
+			}
+			f.name, f.property = prop, true
+		} else if reservedFields[parts[0]] {
+			f.name = parts[0]
+		} else {
+			return nil, fmt.Errorf("unleash: unknown context field %q on field %s.%s", parts[0], t, sf.Name)
+		}
+
+		format, err := formatter(sf.Type)
+		if err != nil {
+			return nil, fmt.Errorf("unleash: field %s.%s: %w", t, sf.Name, err)
+		}
+		f.format = format
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// formatter returns the function converting values of type t to strings. The
+// boolean it returns is false when there is nothing to set, such as for a nil
+// pointer or interface.
+func formatter(t reflect.Type) (func(v reflect.Value) (string, bool), error) {
+	if t.Kind() == reflect.Ptr {
+		elem, err := formatter(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return func(v reflect.Value) (string, bool) {
+			if v.IsNil() {
+				return "", false
+			}
+			return elem(v.Elem())
+		}, nil
+	}
+	if t.Kind() == reflect.Interface {
+		format, err := valueFormatter(t)
+		if err != nil {
+			return nil, err
+		}
+		return func(v reflect.Value) (string, bool) {
+			if v.IsNil() {
+				return "", false
+			}
+			return format(v)
+		}, nil
+	}
+	return valueFormatter(t)
+}
+
+// valueFormatter returns the function converting values of type t, which is not a
+// pointer, to strings.
+func valueFormatter(t reflect.Type) (func(v reflect.Value) (string, bool), error) {
+
+	switch {
+	case t == timeType:
+		return func(v reflect.Value) (string, bool) {
+			return v.Interface().(time.Time).UTC().Format(time.RFC3339), true
+		}, nil
+	case t.Implements(stringerType):
+		return func(v reflect.Value) (string, bool) {
+			return v.Interface().(fmt.Stringer).String(), true
+		}, nil
+	case t.Implements(textMarshalerType):
+		return func(v reflect.Value) (string, bool) {
+			text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			return string(text), err == nil
+		}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) (string, bool) {
+			return v.String(), true
+		}, nil
+	case reflect.Bool:
+		return func(v reflect.Value) (string, bool) {
+			return strconv.FormatBool(v.Bool()), true
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) (string, bool) {
+			return strconv.FormatInt(v.Int(), 10), true
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value) (string, bool) {
+			return strconv.FormatUint(v.Uint(), 10), true
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		bits := t.Bits()
+		return func(v reflect.Value) (string, bool) {
+			return strconv.FormatFloat(v.Float(), 'f', -1, bits), true
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
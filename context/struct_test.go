@@ -0,0 +1,112 @@
+package context
+
+import (
+	"encoding"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type version struct {
+	major, minor, patch int
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+type Organization struct {
+	Tenant string `unleash:"prop=tenant"`
+}
+
+type user struct {
+	Organization
+	ID         int64     `unleash:"userId"`
+	Session    *string   `unleash:"sessionId"`
+	Plan       string    `unleash:"prop=plan"`
+	Score      float64   `unleash:"prop=score"`
+	Beta       bool      `unleash:"prop=beta"`
+	SignedUp   time.Time `unleash:"prop=signedUp,omitempty"`
+	AppVersion version   `unleash:"prop=appVersion"`
+	Ignored    string
+	Skipped    string `unleash:"-"`
+}
+
+func TestFromStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	u := user{
+		Organization: Organization{Tenant: "acme"},
+		ID:           42,
+		Plan:         "pro",
+		Score:        0.5,
+		Beta:         true,
+		SignedUp:     time.Date(2022, 1, 2, 3, 4, 5, 6, time.FixedZone("CET", 3600)),
+		AppVersion:   version{1, 2, 3},
+		Ignored:      "ignored",
+		Skipped:      "skipped",
+	}
+
+	ctx, err := FromStruct(&u)
+	assert.NoError(err)
+	assert.Equal(Context{
+		UserId: "42",
+		Properties: map[string]string{
+			"tenant":     "acme",
+			"plan":       "pro",
+			"score":      "0.5",
+			"beta":       "true",
+			"signedUp":   "2022-01-02T02:04:05Z",
+			"appVersion": "1.2.3",
+		},
+	}, ctx)
+
+	session := "abc"
+	u.Session = &session
+	u.SignedUp = time.Time{}
+	ctx, err = FromStruct(u)
+	assert.NoError(err)
+	assert.Equal("abc", ctx.SessionId)
+	assert.NotContains(ctx.Properties, "signedUp")
+
+	type account struct {
+		Version fmt.Stringer           `unleash:"prop=version"`
+		Region  fmt.Stringer           `unleash:"prop=region"`
+		Since   encoding.TextMarshaler `unleash:"prop=since"`
+	}
+	ctx, err = FromStruct(account{Version: version{1, 0, 0}})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"version": "1.0.0"}, ctx.Properties, "nil interfaces are not set")
+}
+
+func TestFromStruct_Errors(t *testing.T) {
+	_, err := FromStruct("not a struct")
+	assert.Error(t, err)
+
+	_, err = FromStruct((*user)(nil))
+	assert.Error(t, err)
+
+	_, err = FromStruct(struct {
+		ID string `unleash:"id"`
+	}{})
+	assert.Error(t, err, "unknown context field")
+
+	_, err = FromStruct(struct {
+		Tags []string `unleash:"prop=tags"`
+	}{})
+	assert.Error(t, err, "unsupported type")
+
+	_, err = FromStruct(struct {
+		Plan string `unleash:"prop=plan,required"`
+	}{})
+	assert.Error(t, err, "unknown option")
+}
+
+func BenchmarkFromStruct(b *testing.B) {
+	u := user{ID: 42, Plan: "pro", AppVersion: version{1, 2, 3}}
+	for i := 0; i < b.N; i++ {
+		_, _ = FromStruct(&u)
+	}
+}
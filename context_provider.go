@@ -0,0 +1,118 @@
+package unleash
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// ContextProvider returns process-level context data, such as the hostname, the
+// region or the build version, that enriches every evaluation of the client.
+type ContextProvider func() context.Context
+
+// EnvContextProvider returns a provider setting properties from environment
+// variables. The keys of the mapping are the property names and the values the
+// names of the environment variables. Unset variables are ignored.
+func EnvContextProvider(mapping map[string]string) ContextProvider {
+	return func() context.Context {
+		props := make(map[string]string, len(mapping))
+		for name, env := range mapping {
+			if value, ok := os.LookupEnv(env); ok {
+				props[name] = value
+			}
+		}
+		return context.Context{Properties: props}
+	}
+}
+
+// HostnameContextProvider returns a provider setting the hostname of the machine
+// as the given property. Nothing is set if the hostname cannot be determined.
+func HostnameContextProvider(property string) ContextProvider {
+	return func() context.Context {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return context.Context{}
+		}
+		return context.Context{Properties: map[string]string{property: hostname}}
+	}
+}
+
+type contextProvider struct {
+	provide         ContextProvider
+	refreshInterval time.Duration
+}
+
+// contextProviders computes the static context of a client. The layers are, by
+// increasing precedence: the environment and application name of the client,
+// then every provider in the order they were registered. Properties are merged
+// rather than replaced between layers.
+type contextProviders struct {
+	base      context.Context
+	providers []contextProvider
+	mu        sync.RWMutex
+	values    []context.Context
+	current   *context.Context
+}
+
+func newContextProviders(base context.Context, providers []contextProvider) *contextProviders {
+	cp := &contextProviders{
+		base:      base,
+		providers: providers,
+		values:    make([]context.Context, len(providers)),
+	}
+	for i, p := range providers {
+		cp.values[i] = p.provide()
+	}
+	cp.current = cp.merge()
+	return cp
+}
+
+// merge layers the provided values over the base context. It must be called
+// with the lock held.
+func (cp *contextProviders) merge() *context.Context {
+	ctx := &cp.base
+	for _, value := range cp.values {
+		ctx = ctx.Override(value, context.MergeProperties())
+	}
+	return ctx
+}
+
+func (cp *contextProviders) get() *context.Context {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return cp.current
+}
+
+func (cp *contextProviders) refresh(i int) {
+	value := cp.providers[i].provide()
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.values[i] = value
+	cp.current = cp.merge()
+}
+
+// start refreshes the providers having a refresh interval until close is closed.
+func (cp *contextProviders) start(c clock.Clock, close <-chan struct{}, wg *sync.WaitGroup) {
+	for i, p := range cp.providers {
+		if p.refreshInterval <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, interval time.Duration) {
+			defer wg.Done()
+			ticker := c.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C():
+					cp.refresh(i)
+				case <-close:
+					return
+				}
+			}
+		}(i, p.refreshInterval)
+	}
+}
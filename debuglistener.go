@@ -7,6 +7,8 @@ import (
 // DebugListener is an implementation of all of the listener interfaces that simply logs
 // debug info to stdout. It is meant for debugging purposes and an example of implementing
 // the listener interfaces.
+//
+// Deprecated: Use SlogListener, which logs to a structured logger.
 type DebugListener struct{}
 
 // OnError prints out errors.
@@ -38,3 +40,8 @@ func (l DebugListener) OnSent(payload MetricsData) {
 func (l DebugListener) OnRegistered(payload ClientData) {
 	fmt.Printf("Registered: %+v\n", payload)
 }
+
+// OnUpdate prints to the console when the feature toggles changed.
+func (l DebugListener) OnUpdate(diff RepositoryDiff) {
+	fmt.Printf("Updated: %+v\n", diff)
+}
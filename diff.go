@@ -0,0 +1,145 @@
+package unleash
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+)
+
+// RepositoryDiff describes how the feature toggles and segments of the repository
+// changed after a fetch. Feature toggles are identified by name and segments by
+// id, and every list is sorted.
+type RepositoryDiff struct {
+	// Added lists the feature toggles that did not exist before.
+	Added []string
+
+	// Removed lists the feature toggles that no longer exist.
+	Removed []string
+
+	// Enabled lists the existing feature toggles that were switched on.
+	Enabled []string
+
+	// Disabled lists the existing feature toggles that were switched off.
+	Disabled []string
+
+	// StrategiesChanged lists the existing feature toggles whose strategies
+	// changed, including their constraints, segments and parameters.
+	StrategiesChanged []string
+
+	// VariantsChanged lists the existing feature toggles whose variants changed.
+	VariantsChanged []string
+
+	// SegmentsAdded lists the segments that did not exist before.
+	SegmentsAdded []int
+
+	// SegmentsRemoved lists the segments that no longer exist.
+	SegmentsRemoved []int
+
+	// SegmentsChanged lists the existing segments whose constraints changed.
+	SegmentsChanged []int
+}
+
+// Empty reports whether nothing changed.
+func (d RepositoryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 &&
+		len(d.Enabled) == 0 && len(d.Disabled) == 0 &&
+		len(d.StrategiesChanged) == 0 && len(d.VariantsChanged) == 0 &&
+		len(d.SegmentsAdded) == 0 && len(d.SegmentsRemoved) == 0 && len(d.SegmentsChanged) == 0
+}
+
+// Changed reports whether the feature toggle was added, removed or modified.
+// Changes of the segments it uses are not taken into account.
+func (d RepositoryDiff) Changed(feature string) bool {
+	for _, names := range [][]string{d.Added, d.Removed, d.Enabled, d.Disabled, d.StrategiesChanged, d.VariantsChanged} {
+		if contains(names, feature) {
+			return true
+		}
+	}
+	return false
+}
+
+// Flipped reports whether an existing feature toggle was switched on or off, and
+// its new state.
+func (d RepositoryDiff) Flipped(feature string) (enabled bool, flipped bool) {
+	if contains(d.Enabled, feature) {
+		return true, true
+	}
+	if contains(d.Disabled, feature) {
+		return false, true
+	}
+	return false, false
+}
+
+// diffRepository computes the changes between two versions of the repository.
+func diffRepository(oldFeatures, newFeatures []api.Feature, oldSegments, newSegments []api.Segment) RepositoryDiff {
+	var d RepositoryDiff
+
+	previous := make(map[string]api.Feature, len(oldFeatures))
+	for _, f := range oldFeatures {
+		previous[f.Name] = f
+	}
+	current := make(map[string]bool, len(newFeatures))
+	for _, f := range newFeatures {
+		current[f.Name] = true
+		old, ok := previous[f.Name]
+		if !ok {
+			d.Added = append(d.Added, f.Name)
+			continue
+		}
+		if old.Enabled != f.Enabled {
+			if f.Enabled {
+				d.Enabled = append(d.Enabled, f.Name)
+			} else {
+				d.Disabled = append(d.Disabled, f.Name)
+			}
+		}
+		if !sameSlice(old.Strategies, f.Strategies) {
+			d.StrategiesChanged = append(d.StrategiesChanged, f.Name)
+		}
+		if !sameSlice(old.Variants, f.Variants) {
+			d.VariantsChanged = append(d.VariantsChanged, f.Name)
+		}
+	}
+	for _, f := range oldFeatures {
+		if !current[f.Name] {
+			d.Removed = append(d.Removed, f.Name)
+		}
+	}
+
+	previousSegments := make(map[int]api.Segment, len(oldSegments))
+	for _, s := range oldSegments {
+		previousSegments[s.Id] = s
+	}
+	currentSegments := make(map[int]bool, len(newSegments))
+	for _, s := range newSegments {
+		currentSegments[s.Id] = true
+		old, ok := previousSegments[s.Id]
+		if !ok {
+			d.SegmentsAdded = append(d.SegmentsAdded, s.Id)
+		} else if !sameSlice(old.Constraints, s.Constraints) {
+			d.SegmentsChanged = append(d.SegmentsChanged, s.Id)
+		}
+	}
+	for _, s := range oldSegments {
+		if !currentSegments[s.Id] {
+			d.SegmentsRemoved = append(d.SegmentsRemoved, s.Id)
+		}
+	}
+
+	for _, names := range [][]string{d.Added, d.Removed, d.Enabled, d.Disabled, d.StrategiesChanged, d.VariantsChanged} {
+		sort.Strings(names)
+	}
+	for _, ids := range [][]int{d.SegmentsAdded, d.SegmentsRemoved, d.SegmentsChanged} {
+		sort.Ints(ids)
+	}
+	return d
+}
+
+// sameSlice compares two decoded slices, treating nil and empty slices as equal.
+func sameSlice[T any](a, b []T) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
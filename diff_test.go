@@ -0,0 +1,69 @@
+package unleash
+
+import (
+	"testing"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRepository(t *testing.T) {
+	assert := assert.New(t)
+
+	defaultStrategy := []api.Strategy{{Name: "default"}}
+	oldFeatures := []api.Feature{
+		{Name: "unchanged", Enabled: true, Strategies: defaultStrategy},
+		{Name: "removed", Enabled: true},
+		{Name: "switched-on", Enabled: false},
+		{Name: "switched-off", Enabled: true},
+		{Name: "strategies", Enabled: true, Strategies: defaultStrategy},
+		{Name: "variants", Enabled: true, Variants: []api.VariantInternal{{Variant: api.Variant{Name: "a"}}}},
+	}
+	newFeatures := []api.Feature{
+		{Name: "unchanged", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+		{Name: "added", Enabled: true},
+		{Name: "switched-on", Enabled: true, Strategies: []api.Strategy{}},
+		{Name: "switched-off", Enabled: false},
+		{Name: "strategies", Enabled: true, Strategies: []api.Strategy{{Name: "default", Segments: []int{1}}}},
+		{Name: "variants", Enabled: true, Variants: []api.VariantInternal{{Variant: api.Variant{Name: "b"}}}},
+	}
+	oldSegments := []api.Segment{
+		{Id: 1, Constraints: []api.Constraint{{ContextName: "userId", Operator: api.OperatorIn, Values: []string{"1"}}}},
+		{Id: 2},
+		{Id: 3},
+	}
+	newSegments := []api.Segment{
+		{Id: 1, Constraints: []api.Constraint{{ContextName: "userId", Operator: api.OperatorIn, Values: []string{"1", "2"}}}},
+		{Id: 3},
+		{Id: 4},
+	}
+
+	diff := diffRepository(oldFeatures, newFeatures, oldSegments, newSegments)
+	assert.Equal(RepositoryDiff{
+		Added:             []string{"added"},
+		Removed:           []string{"removed"},
+		Enabled:           []string{"switched-on"},
+		Disabled:          []string{"switched-off"},
+		StrategiesChanged: []string{"strategies"},
+		VariantsChanged:   []string{"variants"},
+		SegmentsAdded:     []int{4},
+		SegmentsRemoved:   []int{2},
+		SegmentsChanged:   []int{1},
+	}, diff)
+
+	assert.False(diff.Empty())
+	assert.True(diff.Changed("added"))
+	assert.True(diff.Changed("strategies"))
+	assert.False(diff.Changed("unchanged"))
+
+	enabled, flipped := diff.Flipped("switched-on")
+	assert.True(enabled)
+	assert.True(flipped)
+	enabled, flipped = diff.Flipped("switched-off")
+	assert.False(enabled)
+	assert.True(flipped)
+	_, flipped = diff.Flipped("variants")
+	assert.False(flipped)
+
+	assert.True(diffRepository(oldFeatures, oldFeatures, oldSegments, oldSegments).Empty())
+}
@@ -20,15 +20,16 @@ Go routines. There are two ways this can be done.
 # Using the Listener Interfaces
 
 The first and perhaps simplest way to "drive" the synchronization loop in the client is to provide a type
-that implements one or more of the listener interfaces. There are 3 interfaces and you can choose which ones
+that implements one or more of the listener interfaces. There are 4 interfaces and you can choose which ones
 you should implement:
   - ErrorListener
   - RepositoryListener
   - MetricsListener
+  - UpdateListener
 
 If you are only interesting in tracking errors and warnings and don't care about any of the other signals,
-then you only need to implement the ErrorListener and pass this instance to WithListener(). The DebugListener
-shows an example of implementing all of the listeners in a single type.
+then you only need to implement the ErrorListener and pass this instance to WithListener(). The SlogListener
+implements all of the listeners in a single type and logs every event with a structured logger.
 
 # Reading the channels directly
 
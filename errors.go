@@ -0,0 +1,133 @@
+package unleash
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
+)
+
+// ConstraintError reports a constraint that could not be checked. See
+// evaluator.ConstraintError.
+type ConstraintError = evaluator.ConstraintError
+
+// SegmentNotFoundError reports a strategy referencing a missing segment. See
+// evaluator.SegmentNotFoundError.
+type SegmentNotFoundError = evaluator.SegmentNotFoundError
+
+// StrategyNotFoundError reports a strategy not implemented by the client. See
+// evaluator.StrategyNotFoundError.
+type StrategyNotFoundError = evaluator.StrategyNotFoundError
+
+// FetchError reports that the feature toggles could not be fetched because the
+// Unleash server answered with an unexpected status code.
+type FetchError struct {
+	// Method is the method of the request.
+	Method string
+
+	// URL is the requested URL.
+	URL string
+
+	// StatusCode is the status code of the response.
+	StatusCode int
+
+	// detail describes how the client reacts to the status code.
+	detail string
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("%s %s returned status code %d%s", e.Method, e.URL, e.StatusCode, e.detail)
+}
+
+// StaleDataError reports that the feature toggles were not refreshed for longer
+// than the maximum age set with WithMaxDataAge.
+type StaleDataError struct {
+	// Age is the age of the feature toggles, or zero if it is unknown, as for
+	// feature toggles from a bootstrap that were never fetched.
+	Age time.Duration
+
+	// MaxAge is the maximum age.
+	MaxAge time.Duration
+
+	// LastFetchSuccess is the time of the last successful fetch, or zero if
+	// there was none.
+	LastFetchSuccess time.Time
+}
+
+func (e *StaleDataError) Error() string {
+	return fmt.Sprintf("feature toggles were not refreshed for %s, more than the maximum age of %s", e.Age, e.MaxAge)
+}
+
+// maxLimitedErrors bounds the number of distinct errors the limiter remembers.
+const maxLimitedErrors = 1024
+
+// errorLimiter drops the reports of an error that was already reported within
+// the interval, so that a broken toggle evaluated in a hot path does not flood
+// the listener.
+type errorLimiter struct {
+	clock    clock.Clock
+	interval time.Duration
+	mu       sync.Mutex
+	last     map[errorKey]time.Time
+}
+
+// errorKey identifies an error independently of the context it was reported
+// for: the message of a constraint error embeds the failing context value.
+type errorKey struct {
+	kind       string
+	feature    string
+	strategyId int
+	detail     string
+}
+
+func limiterKey(err error) errorKey {
+	switch e := err.(type) {
+	case *ConstraintError:
+		return errorKey{"constraint", e.Feature, e.StrategyId, e.Constraint.ContextName + " " + string(e.Constraint.Operator)}
+	case *SegmentNotFoundError:
+		return errorKey{"segment", e.Feature, e.StrategyId, strconv.Itoa(e.SegmentId)}
+	case *StrategyNotFoundError:
+		return errorKey{"strategy", e.Feature, e.StrategyId, e.Strategy}
+	}
+	return errorKey{kind: "other", detail: err.Error()}
+}
+
+func newErrorLimiter(c clock.Clock, interval time.Duration) *errorLimiter {
+	return &errorLimiter{clock: c, interval: interval, last: map[errorKey]time.Time{}}
+}
+
+// allow reports whether the error should be reported now.
+func (l *errorLimiter) allow(err error) bool {
+	if l.interval <= 0 {
+		return true
+	}
+	key := limiterKey(err)
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.last[key]; ok && now.Sub(last) < l.interval {
+		return false
+	}
+	if len(l.last) >= maxLimitedErrors {
+		l.prune(now)
+	}
+	l.last[key] = now
+	return true
+}
+
+// prune forgets the errors whose interval is over, or every error if all of them
+// were reported within the interval.
+func (l *errorLimiter) prune(now time.Time) {
+	for key, last := range l.last {
+		if now.Sub(last) >= l.interval {
+			delete(l.last, key)
+		}
+	}
+	if len(l.last) >= maxLimitedErrors {
+		l.last = map[errorKey]time.Time{}
+	}
+}
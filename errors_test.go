@@ -0,0 +1,26 @@
+package unleash
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorLimiter_Bounded(t *testing.T) {
+	assert := assert.New(t)
+
+	fake := clock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := newErrorLimiter(fake, time.Minute)
+
+	for i := 0; i < 3*maxLimitedErrors; i++ {
+		assert.True(limiter.allow(fmt.Errorf("error %d", i)))
+	}
+	assert.True(len(limiter.last) <= maxLimitedErrors)
+
+	fake.Advance(time.Minute)
+	assert.True(limiter.allow(fmt.Errorf("another error")))
+	assert.Len(limiter.last, 1, "the errors whose interval is over are forgotten")
+}
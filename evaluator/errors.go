@@ -0,0 +1,67 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+)
+
+// ConstraintError reports a constraint that could not be checked, for instance
+// because a value could not be parsed for its operator.
+type ConstraintError struct {
+	// Feature is the name of the evaluated feature toggle.
+	Feature string
+
+	// StrategyId is the id of the strategy holding the constraint, either
+	// directly or through a segment.
+	StrategyId int
+
+	// Constraint is the failing constraint.
+	Constraint api.Constraint
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("feature toggle %q: strategy %d: constraint on %q with operator %s: %v",
+		e.Feature, e.StrategyId, e.Constraint.ContextName, e.Constraint.Operator, e.Err)
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// SegmentNotFoundError reports a strategy referencing a segment that is not part
+// of the snapshot. The strategy is not evaluated.
+type SegmentNotFoundError struct {
+	// Feature is the name of the evaluated feature toggle.
+	Feature string
+
+	// StrategyId is the id of the strategy referencing the segment.
+	StrategyId int
+
+	// SegmentId is the id of the missing segment.
+	SegmentId int
+}
+
+func (e *SegmentNotFoundError) Error() string {
+	return fmt.Sprintf("feature toggle %q: strategy %d: segment %d does not exist", e.Feature, e.StrategyId, e.SegmentId)
+}
+
+// StrategyNotFoundError reports a strategy that is not implemented by the
+// evaluator. It is skipped, as if it was not enabled for the context.
+type StrategyNotFoundError struct {
+	// Feature is the name of the evaluated feature toggle.
+	Feature string
+
+	// StrategyId is the id of the strategy.
+	StrategyId int
+
+	// Strategy is the name of the strategy.
+	Strategy string
+}
+
+func (e *StrategyNotFoundError) Error() string {
+	return fmt.Sprintf("feature toggle %q: strategy %d: strategy %q is not implemented", e.Feature, e.StrategyId, e.Strategy)
+}
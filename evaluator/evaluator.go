@@ -0,0 +1,467 @@
+// Package evaluator evaluates feature toggles against a snapshot of feature
+// toggles and segments. It does not fetch toggles or report metrics, which makes
+// it usable in batch jobs and tools, and it is what the client uses internally.
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/Unleash/unleash-client-go/v4/internal/constraints"
+	s "github.com/Unleash/unleash-client-go/v4/internal/strategies"
+	"github.com/Unleash/unleash-client-go/v4/strategy"
+)
+
+// DefaultStrategies returns new instances of the built-in activation strategies.
+func DefaultStrategies() []strategy.Strategy {
+	return []strategy.Strategy{
+		*s.NewDefaultStrategy(),
+		*s.NewApplicationHostnameStrategy(),
+		*s.NewGradualRolloutRandomStrategy(),
+		*s.NewGradualRolloutSessionId(),
+		*s.NewGradualRolloutUserId(),
+		*s.NewRemoteAddressStrategy(),
+		*s.NewUserWithIdStrategy(),
+		*s.NewFlexibleRolloutStrategy(),
+	}
+}
+
+// seededStrategies returns new instances of the built-in activation strategies
+// whose random numbers are derived from the seed.
+func seededStrategies(seed int64) []strategy.Strategy {
+	return []strategy.Strategy{
+		*s.NewDefaultStrategy(),
+		*s.NewApplicationHostnameStrategy(),
+		*s.NewSeededGradualRolloutRandomStrategy(seed),
+		*s.NewGradualRolloutSessionId(),
+		*s.NewGradualRolloutUserId(),
+		*s.NewRemoteAddressStrategy(),
+		*s.NewUserWithIdStrategy(),
+		*s.NewSeededFlexibleRolloutStrategy(seed),
+	}
+}
+
+// Evaluator evaluates feature toggles against an immutable snapshot. It is safe
+// for concurrent use as long as the strategies are.
+type Evaluator struct {
+	features   map[string]api.Feature
+	segments   map[int][]api.Constraint
+	strategies []strategy.Strategy
+	checker    constraints.Checker
+	unknown    []api.Operator
+}
+
+type options struct {
+	strategies []strategy.Strategy
+	clock      clock.Clock
+	operators  map[api.Operator]constraints.Operator
+}
+
+// ConstraintOperator checks whether the context fulfills a constraint using a
+// custom operator. The evaluator inverts the result of Inverted constraints, and
+// lower-cases the context value and the constraint values of CaseInsensitive
+// constraints before calling it.
+type ConstraintOperator func(ctx *context.Context, constraint api.Constraint) (bool, error)
+
+// Option configures an Evaluator.
+type Option func(*options)
+
+// WithStrategies specifies which strategies (in addition to the defaults) should
+// be used by the evaluator. The built-in strategies take precedence over custom
+// strategies with the same name.
+func WithStrategies(strategies ...strategy.Strategy) Option {
+	return func(o *options) {
+		o.strategies = strategies
+	}
+}
+
+// WithClock sets the clock used by date constraints when the context has no
+// current time, and to seed the random numbers of the built-in strategies, which
+// makes random stickiness deterministic with a fake clock.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithConstraintOperator registers a custom constraint operator, such as
+// REGEX_MATCH, used by both strategy and segment constraints. The built-in
+// operators take precedence over custom operators with the same name.
+func WithConstraintOperator(operator api.Operator, fn ConstraintOperator) Option {
+	return func(o *options) {
+		if o.operators == nil {
+			o.operators = make(map[api.Operator]constraints.Operator)
+		}
+		o.operators[operator] = constraints.Operator(fn)
+	}
+}
+
+// New creates an evaluator for the features and segments of the snapshot.
+func New(snapshot api.FeatureResponse, opts ...Option) *Evaluator {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	features := make(map[string]api.Feature, len(snapshot.Features))
+	for _, f := range snapshot.Features {
+		features[f.Name] = f
+	}
+
+	e := &Evaluator{
+		features: features,
+		segments: snapshot.SegmentsMap(),
+		checker:  constraints.Checker{Operators: o.operators},
+	}
+	if o.clock != nil {
+		e.checker.Now = o.clock.Now
+		e.strategies = append(seededStrategies(o.clock.Now().UnixNano()), o.strategies...)
+	} else {
+		e.strategies = append(DefaultStrategies(), o.strategies...)
+	}
+	e.unknown = e.unknownOperators(snapshot)
+	return e
+}
+
+// UnknownOperators returns the sorted constraint operators of the snapshot that
+// are neither built in nor registered with WithConstraintOperator. Constraints
+// using them never match.
+func (e *Evaluator) UnknownOperators() []api.Operator {
+	return e.unknown
+}
+
+func (e *Evaluator) unknownOperators(snapshot api.FeatureResponse) []api.Operator {
+	seen := map[api.Operator]bool{}
+	check := func(cs []api.Constraint) {
+		for _, c := range cs {
+			if !e.checker.IsKnown(c.Operator) {
+				seen[c.Operator] = true
+			}
+		}
+	}
+	for _, f := range snapshot.Features {
+		for _, st := range f.Strategies {
+			check(st.Constraints)
+		}
+	}
+	for _, s := range snapshot.Segments {
+		check(s.Constraints)
+	}
+
+	var unknown []api.Operator
+	for op := range seen {
+		unknown = append(unknown, op)
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i] < unknown[j] })
+	return unknown
+}
+
+// Feature returns the definition of the named feature toggle, or nil if it is
+// not part of the snapshot.
+func (e *Evaluator) Feature(name string) *api.Feature {
+	if f, ok := e.features[name]; ok {
+		return &f
+	}
+	return nil
+}
+
+// Result is the outcome of evaluating a feature toggle.
+type Result struct {
+	// Feature is the evaluated definition, nil if the toggle was not found.
+	Feature *api.Feature
+
+	// Enabled indicates whether the toggle is enabled for the context.
+	Enabled bool
+
+	// Variant is the selected variant. Evaluate only sets it when the matching
+	// strategy has variants, Variant also falls back to the variants of the
+	// feature toggle. It is nil if there is no variant to select.
+	Variant *api.Variant
+
+	// Errors holds the errors encountered during the evaluation.
+	Errors []error
+
+	// Warnings holds problems with the definition of the toggle that did not
+	// prevent the evaluation.
+	Warnings []error
+
+	// Reasons lists the steps of the evaluation when requested with WithReasons.
+	Reasons []string
+}
+
+// disabledVariantFeatureEnabled is similar to api.DISABLED_VARIANT but we want
+// to discourage public usage so it's internal until there's a need to expose it.
+var disabledVariantFeatureEnabled = &api.Variant{
+	Name:           "disabled",
+	Enabled:        false,
+	FeatureEnabled: true,
+}
+
+// ResolveVariant returns the variant GetVariant returns for the result. When the
+// toggle is not found or disabled, or no variant was selected, the variant is
+// taken from fallback, called with whether the toggle is enabled. If fallback is
+// nil or returns nil, the disabled variant is returned, marked FeatureEnabled if
+// the toggle is enabled. The returned variant may be shared and must not be
+// modified.
+func (r Result) ResolveVariant(fallback func(featureEnabled bool) *api.Variant) *api.Variant {
+	resolve := func(featureEnabled bool) *api.Variant {
+		if fallback != nil {
+			if v := fallback(featureEnabled); v != nil {
+				return v
+			}
+		}
+		if featureEnabled {
+			return disabledVariantFeatureEnabled
+		}
+		return api.GetDefaultVariant()
+	}
+
+	if r.Feature == nil || !r.Enabled {
+		return resolve(false)
+	}
+	if r.Variant != nil {
+		return r.Variant
+	}
+	return resolve(true)
+}
+
+func (r *Result) explain(reason string) {
+	if r.Reasons != nil {
+		r.Reasons = append(r.Reasons, reason)
+	}
+}
+
+type evaluationOptions struct {
+	feature *api.Feature
+	reasons bool
+}
+
+// EvaluationOption configures a single evaluation.
+type EvaluationOption func(*evaluationOptions)
+
+// WithFeature evaluates the given definition instead of looking the toggle up in
+// the snapshot. Dependencies and segments are still resolved from the snapshot.
+func WithFeature(feature *api.Feature) EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.feature = feature
+	}
+}
+
+// WithReasons records the steps of the evaluation in Result.Reasons.
+func WithReasons() EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.reasons = true
+	}
+}
+
+// Evaluate checks whether the named feature toggle is enabled for the context.
+func (e *Evaluator) Evaluate(name string, ctx *context.Context, opts ...EvaluationOption) Result {
+	var o evaluationOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if ctx == nil {
+		ctx = &context.Context{}
+	}
+
+	var r Result
+	if o.reasons {
+		r.Reasons = []string{}
+	}
+
+	f := o.feature
+	if f == nil {
+		f = e.Feature(name)
+	}
+	if f == nil {
+		r.explain("feature toggle not found")
+		return r
+	}
+
+	e.evaluate(f, ctx, &r)
+	return r
+}
+
+// Variant evaluates the named feature toggle like Evaluate and selects the
+// variant for the context, either from the matching strategy or from the feature
+// toggle itself.
+func (e *Evaluator) Variant(name string, ctx *context.Context, opts ...EvaluationOption) Result {
+	r := e.Evaluate(name, ctx, opts...)
+	if r.Feature == nil || !r.Enabled || r.Variant != nil || len(r.Feature.Variants) == 0 {
+		return r
+	}
+	if ctx == nil {
+		ctx = &context.Context{}
+	}
+
+	r.Variant = api.VariantCollection{
+		GroupId:  r.Feature.Name,
+		Variants: r.Feature.Variants,
+	}.GetVariant(ctx)
+	return r
+}
+
+func (e *Evaluator) evaluate(f *api.Feature, ctx *context.Context, r *Result) {
+	r.Feature = f
+	trace := r.Reasons != nil
+
+	if f.Dependencies != nil && len(*f.Dependencies) > 0 {
+		if !e.dependenciesSatisfied(f, ctx, r) {
+			r.explain("parent dependencies are not satisfied")
+			return
+		}
+	}
+
+	if !f.Enabled {
+		r.explain("feature toggle is disabled")
+		return
+	}
+
+	if len(f.Strategies) == 0 {
+		r.explain("feature toggle is enabled and has no strategies")
+		r.Enabled = true
+		return
+	}
+
+	for _, st := range f.Strategies {
+		foundStrategy := e.strategy(st.Name)
+		if foundStrategy == nil {
+			r.Warnings = append(r.Warnings, &StrategyNotFoundError{Feature: f.Name, StrategyId: st.Id, Strategy: st.Name})
+			if trace {
+				r.explain(fmt.Sprintf("strategy %q is not implemented by this client", st.Name))
+			}
+			continue
+		}
+
+		segmentConstraints, err := e.resolveSegmentConstraints(f, st)
+		if err != nil {
+			r.Errors = append(r.Errors, err)
+			if trace {
+				r.explain(fmt.Sprintf("strategy %q could not be evaluated: %v", st.Name, err))
+			}
+			return
+		}
+
+		allConstraints := make([]api.Constraint, 0, len(segmentConstraints)+len(st.Constraints))
+		allConstraints = append(allConstraints, segmentConstraints...)
+		allConstraints = append(allConstraints, st.Constraints...)
+
+		if ok, err := e.checker.Check(ctx, allConstraints); errors.Is(err, constraints.ErrUnknownOperator) {
+			// Unknown operators are reported once through UnknownOperators.
+			if trace {
+				r.explain(fmt.Sprintf("constraints of strategy %q are not satisfied: %v", st.Name, err))
+			}
+		} else if err != nil {
+			var constraintErr *constraints.Error
+			if errors.As(err, &constraintErr) {
+				err = &ConstraintError{Feature: f.Name, StrategyId: st.Id, Constraint: constraintErr.Constraint, Err: constraintErr.Err}
+			}
+			r.Errors = append(r.Errors, err)
+			if trace {
+				r.explain(fmt.Sprintf("constraints of strategy %q could not be evaluated: %v", st.Name, err))
+			}
+		} else if !ok {
+			if trace {
+				r.explain(fmt.Sprintf("constraints of strategy %q are not satisfied", st.Name))
+			}
+		} else if foundStrategy.IsEnabled(st.Parameters, ctx) {
+			if len(st.Variants) > 0 {
+				groupId, ok := st.Parameters[strategy.ParamGroupId].(string)
+				if !ok {
+					if trace {
+						r.explain(fmt.Sprintf("strategy %q has variants but no groupId", st.Name))
+					}
+					return
+				}
+
+				if trace {
+					r.explain(fmt.Sprintf("strategy %q is enabled for the context and selects a strategy variant", st.Name))
+				}
+				r.Enabled = true
+				r.Variant = api.VariantCollection{
+					GroupId:  groupId,
+					Variants: st.Variants,
+				}.GetVariant(ctx)
+				return
+			}
+
+			if trace {
+				r.explain(fmt.Sprintf("strategy %q is enabled for the context", st.Name))
+			}
+			r.Enabled = true
+			return
+		} else if trace {
+			r.explain(fmt.Sprintf("strategy %q is not enabled for the context", st.Name))
+		}
+	}
+
+	r.explain("no strategy is enabled for the context")
+}
+
+func (e *Evaluator) dependenciesSatisfied(f *api.Feature, ctx *context.Context, r *Result) bool {
+	for _, parent := range *f.Dependencies {
+		parentToggle := e.Feature(parent.Feature)
+		if parentToggle == nil {
+			r.Warnings = append(r.Warnings, fmt.Errorf("the parent toggle %q of %q was not found, the evaluation of this dependency will always be false", parent.Feature, f.Name))
+			return false
+		}
+
+		if parentToggle.Dependencies != nil && len(*parentToggle.Dependencies) > 0 {
+			return false
+		}
+
+		var parentResult Result
+		e.evaluate(parentToggle, ctx, &parentResult)
+		r.Errors = append(r.Errors, parentResult.Errors...)
+
+		// According to the schema, if the enabled property is absent we assume it's true.
+		if parent.Enabled == nil || *parent.Enabled {
+			if parent.Variants != nil && len(*parent.Variants) > 0 && parentResult.Variant != nil {
+				if !parentResult.Enabled || !contains(*parent.Variants, parentResult.Variant.Name) {
+					return false
+				}
+			} else if !parentResult.Enabled {
+				return false
+			}
+		} else if parentResult.Enabled {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Evaluator) strategy(name string) strategy.Strategy {
+	for _, st := range e.strategies {
+		if st.Name() == name {
+			return st
+		}
+	}
+	return nil
+}
+
+func (e *Evaluator) resolveSegmentConstraints(f *api.Feature, st api.Strategy) ([]api.Constraint, error) {
+	segmentConstraints := []api.Constraint{}
+
+	for _, segmentId := range st.Segments {
+		if resolvedConstraints, ok := e.segments[segmentId]; ok {
+			segmentConstraints = append(segmentConstraints, resolvedConstraints...)
+		} else {
+			return segmentConstraints, &SegmentNotFoundError{Feature: f.Name, StrategyId: st.Id, SegmentId: segmentId}
+		}
+	}
+
+	return segmentConstraints, nil
+}
+
+func contains(arr []string, str string) bool {
+	for _, item := range arr {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package evaluator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+)
+
+type alwaysOn struct{}
+
+func (alwaysOn) Name() string {
+	return "alwaysOn"
+}
+
+func (alwaysOn) IsEnabled(map[string]interface{}, *context.Context) bool {
+	return true
+}
+
+var snapshot = api.FeatureResponse{
+	Features: []api.Feature{
+		{
+			Name:    "segmented",
+			Enabled: true,
+			Strategies: []api.Strategy{
+				{Id: 1, Name: "default", Segments: []int{1}},
+			},
+		},
+		{
+			Name:    "missing-segment",
+			Enabled: true,
+			Strategies: []api.Strategy{
+				{Id: 1, Name: "default", Segments: []int{2}},
+			},
+		},
+		{
+			Name:       "custom",
+			Enabled:    true,
+			Strategies: []api.Strategy{{Id: 1, Name: "alwaysOn"}},
+			Variants: []api.VariantInternal{
+				{Variant: api.Variant{Name: "blue"}, Weight: 1000},
+			},
+		},
+		{
+			Name:         "child",
+			Enabled:      true,
+			Dependencies: &[]api.Dependency{{Feature: "segmented"}},
+		},
+		{
+			Name:         "orphan",
+			Enabled:      true,
+			Dependencies: &[]api.Dependency{{Feature: "does-not-exist"}},
+		},
+	},
+	Segments: []api.Segment{
+		{Id: 1, Constraints: []api.Constraint{
+			{ContextName: "plan", Operator: api.OperatorIn, Values: []string{"pro"}},
+		}},
+	},
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	assert := assert.New(t)
+	e := New(snapshot, WithStrategies(alwaysOn{}))
+
+	pro := &context.Context{Properties: map[string]string{"plan": "pro"}}
+	free := &context.Context{Properties: map[string]string{"plan": "free"}}
+
+	assert.True(e.Evaluate("segmented", pro).Enabled)
+	assert.False(e.Evaluate("segmented", free).Enabled)
+	assert.True(e.Evaluate("child", pro).Enabled)
+	assert.False(e.Evaluate("child", free).Enabled)
+	assert.True(e.Evaluate("custom", nil).Enabled)
+
+	result := e.Evaluate("missing-segment", pro)
+	assert.False(result.Enabled)
+	assert.Len(result.Errors, 1)
+
+	result = e.Evaluate("orphan", pro)
+	assert.False(result.Enabled)
+	assert.Len(result.Warnings, 1)
+
+	result = e.Evaluate("unknown", pro, WithReasons())
+	assert.Nil(result.Feature)
+	assert.Equal([]string{"feature toggle not found"}, result.Reasons)
+
+	result = e.Evaluate("unknown", pro, WithFeature(&api.Feature{Name: "unknown", Enabled: true}))
+	assert.True(result.Enabled)
+	assert.Equal("unknown", result.Feature.Name)
+}
+
+func TestEvaluator_Variant(t *testing.T) {
+	assert := assert.New(t)
+	e := New(snapshot, WithStrategies(alwaysOn{}))
+
+	assert.Nil(New(snapshot).Variant("custom", nil).Variant, "unknown strategies never match")
+
+	result := e.Variant("custom", &context.Context{UserId: "1"})
+	assert.True(result.Enabled)
+	assert.Equal("blue", result.Variant.Name)
+	assert.True(result.Variant.FeatureEnabled)
+
+	result = e.Variant("segmented", &context.Context{Properties: map[string]string{"plan": "pro"}})
+	assert.True(result.Enabled)
+	assert.Nil(result.Variant)
+}
+
+func TestEvaluator_Concurrent(t *testing.T) {
+	e := New(snapshot, WithStrategies(alwaysOn{}))
+	ctx := &context.Context{UserId: "1", Properties: map[string]string{"plan": "pro"}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, f := range snapshot.Features {
+				e.Variant(f.Name, ctx)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEvaluator_WithClock(t *testing.T) {
+	assert := assert.New(t)
+
+	snapshot := api.FeatureResponse{
+		Features: []api.Feature{
+			{
+				Name:    "launch",
+				Enabled: true,
+				Strategies: []api.Strategy{
+					{
+						Id:   1,
+						Name: "default",
+						Constraints: []api.Constraint{
+							{ContextName: "currentTime", Operator: api.OperatorDateAfter, Value: "2022-06-01T00:00:00Z"},
+						},
+					},
+				},
+			},
+			{
+				Name:    "random",
+				Enabled: true,
+				Strategies: []api.Strategy{
+					{Id: 1, Name: "flexibleRollout", Parameters: map[string]interface{}{"rollout": 50, "stickiness": "random"}},
+				},
+			},
+		},
+	}
+
+	fake := clock.NewFake(time.Date(2022, 5, 31, 0, 0, 0, 0, time.UTC))
+	assert.False(New(snapshot, WithClock(fake)).Evaluate("launch", nil).Enabled)
+	fake.Advance(48 * time.Hour)
+	assert.True(New(snapshot, WithClock(fake)).Evaluate("launch", nil).Enabled)
+
+	sample := func(e *Evaluator) []bool {
+		results := make([]bool, 50)
+		for i := range results {
+			results[i] = e.Evaluate("random", nil).Enabled
+		}
+		return results
+	}
+	assert.Equal(sample(New(snapshot, WithClock(fake))), sample(New(snapshot, WithClock(fake))),
+		"random stickiness is deterministic for a given time of the clock")
+}
+
+func TestEvaluator_UnknownOperators(t *testing.T) {
+	assert := assert.New(t)
+
+	e := New(api.FeatureResponse{
+		Features: []api.Feature{
+			{
+				Name:    "future",
+				Enabled: true,
+				Strategies: []api.Strategy{
+					{Id: 1, Name: "default", Constraints: []api.Constraint{{ContextName: "userId", Operator: "FUTURE_OP"}}},
+					{Id: 2, Name: "default", Segments: []int{1}},
+				},
+			},
+		},
+		Segments: []api.Segment{
+			{Id: 1, Constraints: []api.Constraint{{ContextName: "userId", Operator: "OTHER_OP"}, {ContextName: "userId", Operator: "FUTURE_OP"}}},
+		},
+	})
+
+	assert.Equal([]api.Operator{"FUTURE_OP", "OTHER_OP"}, e.UnknownOperators())
+
+	r := e.Evaluate("future", &context.Context{UserId: "1"})
+	assert.False(r.Enabled)
+	assert.Empty(r.Errors, "unknown operators are not reported on every evaluation")
+}
+
+func TestResult_ResolveVariant(t *testing.T) {
+	assert := assert.New(t)
+
+	feature := &api.Feature{Name: "feature", Enabled: true}
+	selected := &api.Variant{Name: "blue", Enabled: true, FeatureEnabled: true}
+	fallback := &api.Variant{Name: "fallback"}
+
+	assert.Equal(&api.Variant{Name: "disabled"}, Result{}.ResolveVariant(nil))
+	assert.Equal(&api.Variant{Name: "disabled"}, Result{Feature: feature}.ResolveVariant(nil))
+	assert.Equal(&api.Variant{Name: "disabled", FeatureEnabled: true}, Result{Feature: feature, Enabled: true}.ResolveVariant(nil))
+	assert.Equal(selected, Result{Feature: feature, Enabled: true, Variant: selected}.ResolveVariant(nil))
+
+	var calls []bool
+	resolve := func(featureEnabled bool) *api.Variant {
+		calls = append(calls, featureEnabled)
+		return fallback
+	}
+	assert.Equal(fallback, Result{}.ResolveVariant(resolve))
+	assert.Equal(fallback, Result{Feature: feature, Enabled: true}.ResolveVariant(resolve))
+	assert.Equal([]bool{false, true}, calls)
+}
@@ -0,0 +1,316 @@
+package unleash
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// OverflowPolicy decides what happens to an event emitted while its queue is
+// full because the listener, or the code draining the channels, is too slow.
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest discards the oldest queued event to make room for the
+	// new one. It is the default policy.
+	OverflowDropOldest OverflowPolicy = iota
+
+	// OverflowDropNewest discards the new event.
+	OverflowDropNewest
+
+	// OverflowBlock waits for room in the queue. Evaluations may then block on a
+	// slow listener. Since the handlers run on the goroutine draining the
+	// queues, a handler must not evaluate feature toggles with this policy: once
+	// a queue is full, the handler would wait for itself forever.
+	OverflowBlock
+)
+
+const defaultEventBufferSize = 100
+
+// DroppedEvents counts the events discarded because their queue was full.
+type DroppedEvents struct {
+	Errors   uint64
+	Warnings uint64
+	Counts   uint64
+	Sent     uint64
+
+	// Events counts the dropped fetched, updated and impression events.
+	Events uint64
+}
+
+// eventQueue is a bounded queue of events applying an overflow policy, so that
+// emitting an event does not block unless OverflowBlock is used.
+type eventQueue[T any] struct {
+	ch      chan T
+	policy  OverflowPolicy
+	dropped uint64
+}
+
+func newEventQueue[T any](size int, policy OverflowPolicy) *eventQueue[T] {
+	if size < 1 {
+		size = 1
+	}
+	return &eventQueue[T]{ch: make(chan T, size), policy: policy}
+}
+
+func (q *eventQueue[T]) push(v T) {
+	switch q.policy {
+	case OverflowBlock:
+		q.ch <- v
+	case OverflowDropNewest:
+		select {
+		case q.ch <- v:
+		default:
+			atomic.AddUint64(&q.dropped, 1)
+		}
+	default:
+		for {
+			select {
+			case q.ch <- v:
+				return
+			default:
+			}
+			select {
+			case <-q.ch:
+				atomic.AddUint64(&q.dropped, 1)
+			default:
+			}
+		}
+	}
+}
+
+// tryPush queues the event like push, except that it drops the event rather
+// than waiting when the queue is full under OverflowBlock.
+func (q *eventQueue[T]) tryPush(v T) {
+	if q.policy != OverflowBlock {
+		q.push(v)
+		return
+	}
+	select {
+	case q.ch <- v:
+	default:
+		atomic.AddUint64(&q.dropped, 1)
+	}
+}
+
+func (q *eventQueue[T]) droppedCount() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+const (
+	// EventError is the type of ErrorEvent.
+	EventError EventType = "error"
+	// EventWarning is the type of WarningEvent.
+	EventWarning EventType = "warning"
+	// EventReady is the type of ReadyEvent.
+	EventReady EventType = "ready"
+	// EventFetched is the type of FetchedEvent.
+	EventFetched EventType = "fetched"
+	// EventUpdated is the type of UpdatedEvent.
+	EventUpdated EventType = "updated"
+	// EventCount is the type of CountEvent.
+	EventCount EventType = "count"
+	// EventSent is the type of SentEvent.
+	EventSent EventType = "sent"
+	// EventRegistered is the type of RegisteredEvent.
+	EventRegistered EventType = "registered"
+	// EventImpression is the type of ImpressionEvent.
+	EventImpression EventType = "impression"
+)
+
+// Event is implemented by every event delivered to the handlers registered with
+// Client.Subscribe.
+type Event interface {
+	// Type returns the type of the event.
+	Type() EventType
+}
+
+// ErrorEvent is emitted when the client experiences an error.
+type ErrorEvent struct {
+	Err error
+}
+
+// WarningEvent is emitted when the client experiences a warning.
+type WarningEvent struct {
+	Err error
+}
+
+// ReadyEvent is emitted when the client has loaded the feature toggles from the
+// Unleash server for the first time.
+type ReadyEvent struct{}
+
+// FetchedEvent is emitted after every successful request for the feature
+// toggles, whether they changed or not.
+type FetchedEvent struct {
+	// NotModified is true when the server reported that the toggles did not
+	// change since the previous fetch.
+	NotModified bool
+
+	// ETag is the version of the toggles reported by the server.
+	ETag string
+}
+
+// UpdatedEvent is emitted when a fetch changed the feature toggles or segments
+// of the repository.
+type UpdatedEvent struct {
+	// ETag is the version of the toggles reported by the server.
+	ETag string
+
+	// Diff describes the changes.
+	Diff RepositoryDiff
+}
+
+// CountEvent is emitted whenever a feature toggle is queried.
+type CountEvent struct {
+	Feature string
+	Enabled bool
+}
+
+// SentEvent is emitted when metrics have been sent to the Unleash server.
+type SentEvent struct {
+	Payload MetricsData
+}
+
+// RegisteredEvent is emitted when the client has registered with the Unleash
+// server.
+type RegisteredEvent struct {
+	Payload ClientData
+}
+
+// ImpressionEvent is emitted whenever a feature toggle with impression data
+// enabled is queried.
+type ImpressionEvent struct {
+	// EventType is either "isEnabled" or "getVariant".
+	EventType string
+
+	// Feature is the name of the feature toggle.
+	Feature string
+
+	// Enabled is the result of the evaluation.
+	Enabled bool
+
+	// Variant is the name of the selected variant, for getVariant impressions.
+	Variant string
+
+	// Context is the context the toggle was evaluated with.
+	Context context.Context
+}
+
+func (ErrorEvent) Type() EventType      { return EventError }
+func (WarningEvent) Type() EventType    { return EventWarning }
+func (ReadyEvent) Type() EventType      { return EventReady }
+func (FetchedEvent) Type() EventType    { return EventFetched }
+func (UpdatedEvent) Type() EventType    { return EventUpdated }
+func (CountEvent) Type() EventType      { return EventCount }
+func (SentEvent) Type() EventType       { return EventSent }
+func (RegisteredEvent) Type() EventType { return EventRegistered }
+func (ImpressionEvent) Type() EventType { return EventImpression }
+
+// EventHandler handles the events of a subscription. Handlers are called one at
+// a time from a single goroutine of the client, so a slow handler delays the
+// delivery of the following events.
+type EventHandler func(Event)
+
+type subscription struct {
+	handler EventHandler
+	types   map[EventType]bool
+}
+
+func (s *subscription) accepts(t EventType) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// eventBus holds the subscriptions of a client.
+type eventBus struct {
+	mu   sync.RWMutex
+	subs []*subscription
+}
+
+func (b *eventBus) subscribe(handler EventHandler, types []EventType) func() {
+	s := &subscription{handler: handler}
+	if len(types) > 0 {
+		s.types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subs = append(b.subs, s)
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			for i, sub := range b.subs {
+				if sub == s {
+					b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+					return
+				}
+			}
+		})
+	}
+}
+
+// publish delivers the event to the matching subscriptions. Handlers are called
+// without holding the lock, so that they may subscribe or unsubscribe.
+func (b *eventBus) publish(e Event) {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	t := e.Type()
+	for _, s := range subs {
+		if s.accepts(t) {
+			s.handler(e)
+		}
+	}
+}
+
+// listenerHandler adapts a listener implementing any of ErrorListener,
+// MetricListener, RepositoryListener and UpdateListener to an EventHandler.
+func listenerHandler(listener interface{}) EventHandler {
+	errorListener, _ := listener.(ErrorListener)
+	metricListener, _ := listener.(MetricListener)
+	repositoryListener, _ := listener.(RepositoryListener)
+	updateListener, _ := listener.(UpdateListener)
+
+	return func(e Event) {
+		switch e := e.(type) {
+		case ErrorEvent:
+			if errorListener != nil {
+				errorListener.OnError(e.Err)
+			}
+		case WarningEvent:
+			if errorListener != nil {
+				errorListener.OnWarning(e.Err)
+			}
+		case ReadyEvent:
+			if repositoryListener != nil {
+				repositoryListener.OnReady()
+			}
+		case CountEvent:
+			if metricListener != nil {
+				metricListener.OnCount(e.Feature, e.Enabled)
+			}
+		case SentEvent:
+			if metricListener != nil {
+				metricListener.OnSent(e.Payload)
+			}
+		case RegisteredEvent:
+			if metricListener != nil {
+				metricListener.OnRegistered(e.Payload)
+			}
+		case UpdatedEvent:
+			if updateListener != nil {
+				updateListener.OnUpdate(e.Diff)
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package unleash
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestEventQueue_OverflowPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	oldest := newEventQueue[int](2, OverflowDropOldest)
+	for i := 1; i <= 4; i++ {
+		oldest.push(i)
+	}
+	assert.Equal(uint64(2), oldest.droppedCount())
+	assert.Equal(3, <-oldest.ch)
+	assert.Equal(4, <-oldest.ch)
+
+	newest := newEventQueue[int](2, OverflowDropNewest)
+	for i := 1; i <= 4; i++ {
+		newest.push(i)
+	}
+	assert.Equal(uint64(2), newest.droppedCount())
+	assert.Equal(1, <-newest.ch)
+	assert.Equal(2, <-newest.ch)
+
+	block := newEventQueue[int](1, OverflowBlock)
+	block.push(1)
+	pushed := make(chan struct{})
+	go func() {
+		block.push(2)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push should block while the queue is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.Equal(1, <-block.ch)
+	<-pushed
+	assert.Equal(2, <-block.ch)
+	assert.Equal(uint64(0), block.droppedCount())
+}
+
+type blockingListener struct {
+	NoopListener
+	release chan struct{}
+}
+
+func (l blockingListener) OnCount(string, bool) {
+	<-l.release
+}
+
+func TestClient_EvaluationDoesNotBlockOnSlowListener(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Post("/client/register").
+		Reply(200)
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{Features: []api.Feature{{Name: "feature", Enabled: true}}})
+
+	listener := blockingListener{release: make(chan struct{})}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithListener(listener),
+		WithMetricsInterval(time.Hour),
+		WithEventBufferSize(10),
+		WithEventOverflowPolicy(OverflowDropNewest),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			client.IsEnabled("feature")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("evaluations are blocked by the listener")
+	}
+
+	// The queue holds ten events, and the listener possibly one more.
+	dropped := client.DroppedEvents().Counts
+	assert.True(dropped == 89 || dropped == 90, "dropped %d events", dropped)
+
+	close(listener.release)
+	assert.NoError(client.Close())
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Post("/client/register").
+		Reply(200)
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		SetHeader("Etag", `"v1"`).
+		JSON(api.FeatureResponse{Features: []api.Feature{
+			{Name: "tracked", Enabled: true, ImpressionData: true, Strategies: []api.Strategy{{Name: "default"}}},
+			{Name: "untracked", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+		}})
+
+	all := make(chan Event, 100)
+	impressions := make(chan Event, 100)
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithRefreshInterval(time.Hour),
+	)
+	assert.NoError(err)
+	client.Subscribe(func(e Event) { all <- e })
+	unsubscribe := client.Subscribe(func(e Event) { impressions <- e }, EventImpression)
+	client.WaitForReady()
+
+	client.IsEnabled("tracked", WithContext(context.Context{UserId: "123"}))
+	client.IsEnabled("untracked")
+	client.GetVariant("tracked")
+
+	e := <-impressions
+	assert.Equal(ImpressionEvent{EventType: "isEnabled", Feature: "tracked", Enabled: true, Context: context.Context{
+		UserId:      "123",
+		Environment: "default",
+		AppName:     mockAppName,
+	}}, e)
+	e = <-impressions
+	assert.Equal(EventImpression, e.Type())
+	assert.Equal("getVariant", e.(ImpressionEvent).EventType)
+	assert.Equal("disabled", e.(ImpressionEvent).Variant)
+
+	unsubscribe()
+	unsubscribe()
+	client.IsEnabled("tracked")
+
+	assert.NoError(client.Close())
+	close(all)
+	close(impressions)
+
+	assert.Len(impressions, 0, "no events are delivered after unsubscribing")
+
+	var types []EventType
+	for e := range all {
+		types = append(types, e.Type())
+	}
+	assert.Contains(types, EventReady)
+	assert.Contains(types, EventFetched)
+	assert.Contains(types, EventUpdated)
+}
+
+func TestClient_SubscribeAlongsideListener(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{})
+
+	listener := errorCollector{errors: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithRefreshInterval(time.Hour),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	subscribed := make(chan error, 10)
+	client.Subscribe(func(e Event) {
+		subscribed <- e.(ErrorEvent).Err
+	}, EventError)
+
+	client.repository.err(errors.New("boom"))
+	assert.EqualError(<-listener.errors, "boom")
+	assert.EqualError(<-subscribed, "boom")
+
+	assert.NoError(client.Close())
+}
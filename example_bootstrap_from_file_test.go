@@ -36,7 +36,7 @@ func Test_bootstrapFromFile(t *testing.T) {
 	gock.New("http://foo.com").
 		Get("/client/features").Persist().Reply(200).BodyString(jsonStr)
 	err = unleash.Initialize(
-		unleash.WithListener(&unleash.DebugListener{}),
+		unleash.WithListener(&unleash.SlogListener{}),
 		unleash.WithAppName("my-application"),
 		unleash.WithRefreshInterval(5*time.Second),
 		unleash.WithDisableMetrics(true),
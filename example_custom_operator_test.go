@@ -0,0 +1,56 @@
+package unleash_test
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4"
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// ipInCidr is fulfilled when the context value is an IP address within one of the
+// CIDR ranges of the constraint.
+func ipInCidr(ctx *context.Context, constraint api.Constraint) (bool, error) {
+	ip := net.ParseIP(ctx.Field(constraint.ContextName))
+	if ip == nil {
+		return false, nil
+	}
+	for _, value := range constraint.Values {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return false, err
+		}
+		if network.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ExampleCustomOperator demonstrates using a custom constraint operator.
+func Example_customOperator() {
+	unleash.Initialize(
+		unleash.WithListener(&unleash.SlogListener{}),
+		unleash.WithAppName("my-application"),
+		unleash.WithUrl("https://unleash.herokuapp.com/api/"),
+		unleash.WithRefreshInterval(5*time.Second),
+		unleash.WithMetricsInterval(5*time.Second),
+		unleash.WithConstraintOperator("IP_IN_CIDR", ipInCidr),
+	)
+
+	ctx := context.Context{
+		RemoteAddress: "10.1.2.3",
+	}
+
+	timer := time.NewTimer(1 * time.Second)
+
+	for {
+		<-timer.C
+		enabled := unleash.IsEnabled("unleash.me", unleash.WithContext(ctx))
+		fmt.Printf("feature is enabled? %v\n", enabled)
+		timer.Reset(1 * time.Second)
+	}
+
+}
@@ -41,7 +41,7 @@ func (s ActiveForUserWithEmailStrategy) IsEnabled(params map[string]interface{},
 // ExampleCustomStrategy demonstrates using a custom strategy.
 func Example_customStrategy() {
 	unleash.Initialize(
-		unleash.WithListener(&unleash.DebugListener{}),
+		unleash.WithListener(&unleash.SlogListener{}),
 		unleash.WithAppName("my-application"),
 		unleash.WithUrl("https://unleash.herokuapp.com/api/"),
 		unleash.WithRefreshInterval(5*time.Second),
@@ -12,7 +12,7 @@ const MissingFeature = "does_not_exist"
 // ExampleFallbackFunc demonstrates how to specify a fallback function.
 func Example_fallbackFunc() {
 	unleash.Initialize(
-		unleash.WithListener(&unleash.DebugListener{}),
+		unleash.WithListener(&unleash.SlogListener{}),
 		unleash.WithAppName("my-application"),
 		unleash.WithUrl("http://unleash.herokuapp.com/api/"),
 	)
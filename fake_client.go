@@ -0,0 +1,207 @@
+package unleash
+
+import (
+	"sync"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// ContextMatcher reports whether a scripted result of a FakeClient applies to the
+// context of a call.
+type ContextMatcher func(ctx context.Context) bool
+
+// ForUser matches the contexts of the given user.
+func ForUser(userId string) ContextMatcher {
+	return func(ctx context.Context) bool {
+		return ctx.UserId == userId
+	}
+}
+
+// ForProperty matches the contexts having the property set to the value.
+func ForProperty(name, value string) ContextMatcher {
+	return func(ctx context.Context) bool {
+		return ctx.Field(name) == value
+	}
+}
+
+// FakeCall is a call recorded by a FakeClient.
+type FakeCall struct {
+	// Method is either "IsEnabled" or "GetVariant".
+	Method string
+
+	// Feature is the name of the queried feature toggle.
+	Feature string
+
+	// Context is the context passed with the call.
+	Context context.Context
+}
+
+type fakeEnabled struct {
+	enabled  bool
+	matchers []ContextMatcher
+}
+
+type fakeVariant struct {
+	variant  *api.Variant
+	matchers []ContextMatcher
+}
+
+func matchAll(matchers []ContextMatcher, ctx context.Context) bool {
+	for _, match := range matchers {
+		if !match(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// FakeClient is an in-memory FeatureClient for application tests. Results are
+// scripted per feature toggle and optionally per context, and every query is
+// recorded. Queries for toggles without a matching scripted result honor the
+// fallback options and otherwise return false or the default variant.
+//
+// It is safe to use a FakeClient from multiple goroutines concurrently.
+type FakeClient struct {
+	mu       sync.Mutex
+	enabled  map[string][]fakeEnabled
+	variants map[string][]fakeVariant
+	features []api.Feature
+	calls    []FakeCall
+	closed   bool
+}
+
+var _ FeatureClient = (*FakeClient)(nil)
+
+// NewFakeClient creates a FakeClient without any scripted results.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		enabled:  map[string][]fakeEnabled{},
+		variants: map[string][]fakeVariant{},
+	}
+}
+
+// SetEnabled scripts the result of IsEnabled for the feature toggle. The result
+// applies to the contexts matched by all the matchers, or to every context if
+// none is given. When several results match, the one set last wins.
+func (c *FakeClient) SetEnabled(feature string, enabled bool, matchers ...ContextMatcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled[feature] = append(c.enabled[feature], fakeEnabled{enabled, matchers})
+}
+
+// SetVariant scripts the result of GetVariant for the feature toggle, with the
+// same matching rules as SetEnabled.
+func (c *FakeClient) SetVariant(feature string, variant *api.Variant, matchers ...ContextMatcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.variants[feature] = append(c.variants[feature], fakeVariant{variant, matchers})
+}
+
+// SetFeatures sets the feature toggles returned by ListFeatures.
+func (c *FakeClient) SetFeatures(features ...api.Feature) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.features = features
+}
+
+// IsEnabled returns the scripted result for the feature toggle and context.
+func (c *FakeClient) IsEnabled(feature string, options ...FeatureOption) bool {
+	var opts featureOption
+	for _, o := range options {
+		o(&opts)
+	}
+	ctx := context.Context{}
+	if opts.ctx != nil {
+		ctx = *opts.ctx
+	}
+
+	c.mu.Lock()
+	c.calls = append(c.calls, FakeCall{Method: "IsEnabled", Feature: feature, Context: ctx})
+	rules := c.enabled[feature]
+	c.mu.Unlock()
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchAll(rules[i].matchers, ctx) {
+			return rules[i].enabled
+		}
+	}
+	return handleFallback(opts, feature, &ctx).Enabled
+}
+
+// GetVariant returns the scripted variant for the feature toggle and context.
+func (c *FakeClient) GetVariant(feature string, options ...VariantOption) *api.Variant {
+	var opts variantOption
+	for _, o := range options {
+		o(&opts)
+	}
+	ctx := context.Context{}
+	if opts.ctx != nil {
+		ctx = *opts.ctx
+	}
+
+	c.mu.Lock()
+	c.calls = append(c.calls, FakeCall{Method: "GetVariant", Feature: feature, Context: ctx})
+	rules := c.variants[feature]
+	c.mu.Unlock()
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchAll(rules[i].matchers, ctx) {
+			return rules[i].variant
+		}
+	}
+	if opts.variantFallbackFunc != nil {
+		return opts.variantFallbackFunc(feature, &ctx)
+	} else if opts.variantFallback != nil {
+		return opts.variantFallback
+	}
+	return api.GetDefaultVariant()
+}
+
+// ListFeatures returns the feature toggles set with SetFeatures, or only those
+// matching every given filter.
+func (c *FakeClient) ListFeatures(filters ...FeatureFilter) []api.Feature {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return filterFeatures(append([]api.Feature(nil), c.features...), filters)
+}
+
+// WaitForReady returns immediately.
+func (c *FakeClient) WaitForReady() {
+}
+
+// Close marks the client as closed.
+func (c *FakeClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// Closed reports whether Close was called.
+func (c *FakeClient) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Calls returns the queries recorded so far, in order.
+func (c *FakeClient) Calls() []FakeCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]FakeCall(nil), c.calls...)
+}
+
+// CallCount returns how many times the feature toggle was queried with either
+// IsEnabled or GetVariant.
+func (c *FakeClient) CallCount(feature string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, call := range c.calls {
+		if call.Feature == feature {
+			n++
+		}
+	}
+	return n
+}
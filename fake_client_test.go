@@ -0,0 +1,42 @@
+package unleash
+
+import (
+	"testing"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClient(t *testing.T) {
+	assert := assert.New(t)
+	var client FeatureClient = NewFakeClient()
+	fake := client.(*FakeClient)
+
+	fake.SetEnabled("checkout", true)
+	fake.SetEnabled("checkout", false, ForUser("blocked"))
+	fake.SetVariant("layout", &api.Variant{Name: "wide", Enabled: true})
+	fake.SetVariant("layout", &api.Variant{Name: "compact", Enabled: true}, ForProperty("device", "mobile"))
+
+	assert.True(client.IsEnabled("checkout"))
+	assert.False(client.IsEnabled("checkout", WithContext(context.Context{UserId: "blocked"})))
+	assert.False(client.IsEnabled("unscripted"))
+	assert.True(client.IsEnabled("unscripted", WithFallback(true)))
+
+	assert.Equal("wide", client.GetVariant("layout").Name)
+	mobile := context.Context{Properties: map[string]string{"device": "mobile"}}
+	assert.Equal("compact", client.GetVariant("layout", WithVariantContext(mobile)).Name)
+	assert.Equal(api.GetDefaultVariant(), client.GetVariant("unscripted"))
+
+	assert.Equal(2, fake.CallCount("checkout"))
+	calls := fake.Calls()
+	assert.Len(calls, 7)
+	assert.Equal(FakeCall{Method: "IsEnabled", Feature: "checkout", Context: context.Context{UserId: "blocked"}}, calls[1])
+
+	withDeclaredFlags(t)
+	flag := BoolFlag("checkout", false)
+	assert.True(flag.Get(client, context.Context{}))
+
+	assert.NoError(client.Close())
+	assert.True(fake.Closed())
+}
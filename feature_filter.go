@@ -0,0 +1,60 @@
+package unleash
+
+import "github.com/Unleash/unleash-client-go/v4/api"
+
+// FeatureFilter selects feature toggles in ListFeatures.
+type FeatureFilter func(api.Feature) bool
+
+// ByProject selects the feature toggles of any of the given projects. A feature
+// toggle without a project belongs to the "default" project.
+func ByProject(projects ...string) FeatureFilter {
+	return func(f api.Feature) bool {
+		project := f.Project
+		if project == "" {
+			project = "default"
+		}
+		return contains(projects, project)
+	}
+}
+
+// ByType selects the feature toggles of any of the given types, such as
+// "release" or "kill-switch".
+func ByType(types ...string) FeatureFilter {
+	return func(f api.Feature) bool {
+		return contains(types, f.Type)
+	}
+}
+
+// ByTag selects the feature toggles with any of the given tags, formatted as
+// type:value.
+func ByTag(tags ...string) FeatureFilter {
+	return func(f api.Feature) bool {
+		for _, tag := range f.Tags {
+			if contains(tags, tag.String()) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// filterFeatures returns the feature toggles matching every filter.
+func filterFeatures(features []api.Feature, filters []FeatureFilter) []api.Feature {
+	if len(filters) == 0 {
+		return features
+	}
+	var matching []api.Feature
+	for _, f := range features {
+		ok := true
+		for _, filter := range filters {
+			if !filter(f) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matching = append(matching, f)
+		}
+	}
+	return matching
+}
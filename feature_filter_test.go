@@ -0,0 +1,96 @@
+package unleash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FetchFilters(t *testing.T) {
+	assert := assert.New(t)
+
+	queries := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/client/features" {
+			return
+		}
+		queries <- req.URL.RawQuery
+		rw.Write([]byte(`{
+			"version": 2,
+			"features": [
+				{"name": "a", "project": "web", "type": "release", "stale": true, "impressionData": true, "tags": [{"type": "simple", "value": "checkout"}]},
+				{"name": "b", "project": "web", "type": "kill-switch", "tags": [{"type": "team", "value": "payments"}]},
+				{"name": "c", "project": "mobile", "type": "release"}
+			]
+		}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithRefreshInterval(time.Hour),
+		WithBackupPath(t.TempDir()),
+		WithProjectName("web"),
+		WithProjects("mobile apps"),
+		WithNamePrefix("a&b"),
+		WithTags("simple:checkout", "team:payments"),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.Equal("namePrefix=a%26b&project=web&project=mobile+apps&tag=simple%3Acheckout&tag=team%3Apayments", <-queries)
+
+	names := func(features []api.Feature) []string {
+		var names []string
+		for _, f := range features {
+			names = append(names, f.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Equal([]string{"a", "b", "c"}, names(client.ListFeatures()))
+	assert.Equal([]string{"a", "b"}, names(client.ListFeatures(ByProject("web"))))
+	assert.Equal([]string{"c"}, names(client.ListFeatures(ByType("release"), ByProject("mobile"))))
+	assert.Equal([]string{"a", "b"}, names(client.ListFeatures(ByTag("simple:checkout", "team:payments"))))
+	assert.Nil(client.ListFeatures(ByTag("simple:other")))
+
+	a := client.ListFeatures(ByTag("simple:checkout"))[0]
+	assert.True(a.Stale)
+	assert.True(a.ImpressionData)
+	assert.Equal([]api.Tag{{Type: "simple", Value: "checkout"}}, a.Tags)
+
+	assert.NoError(client.Close())
+}
+
+func TestClient_InvalidTag(t *testing.T) {
+	for _, tag := range []string{"simple", ":web", "simple:"} {
+		_, err := NewClient(
+			WithUrl(mockerServer),
+			WithAppName(mockAppName),
+			WithTags(tag),
+		)
+		assert.EqualError(t, err, `tag "`+tag+`" must be formatted as type:value`)
+	}
+}
+
+func TestFakeClient_ListFeaturesFilters(t *testing.T) {
+	assert := assert.New(t)
+
+	fake := NewFakeClient()
+	fake.SetFeatures(
+		api.Feature{Name: "a", Project: "web"},
+		api.Feature{Name: "b", Project: "mobile"},
+		api.Feature{Name: "c"},
+	)
+	assert.Len(fake.ListFeatures(), 3)
+	assert.Equal([]api.Feature{{Name: "b", Project: "mobile"}}, fake.ListFeatures(ByProject("mobile")))
+	assert.Equal([]api.Feature{{Name: "c"}}, fake.ListFeatures(ByProject("default")))
+}
@@ -0,0 +1,154 @@
+package unleash
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// Flag is implemented by every typed flag definition. It allows enumerating the
+// flags declared by an application through DeclaredFlags.
+type Flag interface {
+	// Name returns the name of the feature toggle.
+	Name() string
+}
+
+var declaredFlags = struct {
+	sync.Mutex
+	flags map[string]Flag
+}{flags: map[string]Flag{}}
+
+// declareFlag registers the flag. Declaring a name again with the same kind of
+// flag replaces the previous declaration, while declaring it with another kind
+// panics, as the two declarations cannot both evaluate the feature toggle.
+func declareFlag(f Flag) {
+	declaredFlags.Lock()
+	defer declaredFlags.Unlock()
+	if previous, ok := declaredFlags.flags[f.Name()]; ok && reflect.TypeOf(previous) != reflect.TypeOf(f) {
+		panic(fmt.Sprintf("unleash: flag %q declared as both %T and %T", f.Name(), previous, f))
+	}
+	declaredFlags.flags[f.Name()] = f
+}
+
+// DeclaredFlags returns every flag created with BoolFlag or VariantFlag, sorted by
+// name. When flags are declared as package level variables this is an inventory of
+// all the flags used by the application.
+func DeclaredFlags() []Flag {
+	declaredFlags.Lock()
+	defer declaredFlags.Unlock()
+
+	flags := make([]Flag, 0, len(declaredFlags.flags))
+	for _, f := range declaredFlags.flags {
+		flags = append(flags, f)
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name() < flags[j].Name()
+	})
+	return flags
+}
+
+// isNilClient reports whether the client is nil, or a nil pointer to a client
+// such as a *Client that was never created.
+func isNilClient(client FeatureClient) bool {
+	if client == nil {
+		return true
+	}
+	v := reflect.ValueOf(client)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// BoolFlagDefinition is a feature toggle evaluated as a boolean.
+type BoolFlagDefinition struct {
+	name     string
+	fallback bool
+}
+
+// BoolFlag declares a boolean flag with the value to use when the toggle is not
+// found on the Unleash server. It panics if the name is already declared as
+// another kind of flag.
+func BoolFlag(name string, fallback bool) *BoolFlagDefinition {
+	f := &BoolFlagDefinition{name: name, fallback: fallback}
+	declareFlag(f)
+	return f
+}
+
+// Name returns the name of the feature toggle.
+func (f *BoolFlagDefinition) Name() string {
+	return f.name
+}
+
+// Default returns the value used when the toggle is not found.
+func (f *BoolFlagDefinition) Default() bool {
+	return f.fallback
+}
+
+// Get evaluates the flag for the given context. If the client is nil, including
+// a nil *Client, the default value is returned.
+func (f *BoolFlagDefinition) Get(client FeatureClient, ctx context.Context) bool {
+	if isNilClient(client) {
+		return f.fallback
+	}
+	return client.IsEnabled(f.name, WithContext(ctx), WithFallback(f.fallback))
+}
+
+// VariantFlagDefinition is a feature toggle whose variants are mapped to values
+// of type T.
+type VariantFlagDefinition[T any] struct {
+	name     string
+	mapping  map[string]T
+	fallback T
+}
+
+// VariantFlag declares a flag whose variant names are mapped to values of type T.
+// The fallback is returned when the toggle is disabled, not found, or resolves to
+// a variant missing from the mapping. It panics if the name is already declared
+// as another kind of flag.
+func VariantFlag[T any](name string, mapping map[string]T, fallback T) *VariantFlagDefinition[T] {
+	m := make(map[string]T, len(mapping))
+	for k, v := range mapping {
+		m[k] = v
+	}
+	f := &VariantFlagDefinition[T]{name: name, mapping: m, fallback: fallback}
+	declareFlag(f)
+	return f
+}
+
+// Name returns the name of the feature toggle.
+func (f *VariantFlagDefinition[T]) Name() string {
+	return f.name
+}
+
+// Default returns the value used when no mapped variant is resolved.
+func (f *VariantFlagDefinition[T]) Default() T {
+	return f.fallback
+}
+
+// Variants returns the sorted names of the mapped variants.
+func (f *VariantFlagDefinition[T]) Variants() []string {
+	names := make([]string, 0, len(f.mapping))
+	for name := range f.mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get evaluates the flag for the given context and maps the resolved variant to
+// its value. If the client is nil, including a nil *Client, the default value is
+// returned.
+func (f *VariantFlagDefinition[T]) Get(client FeatureClient, ctx context.Context) T {
+	if isNilClient(client) {
+		return f.fallback
+	}
+	variant := client.GetVariant(f.name, WithVariantContext(ctx))
+	if variant == nil || !variant.Enabled {
+		return f.fallback
+	}
+	if value, ok := f.mapping[variant.Name]; ok {
+		return value
+	}
+	return f.fallback
+}
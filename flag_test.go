@@ -0,0 +1,119 @@
+package unleash
+
+import (
+	"testing"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type layoutKind int
+
+const (
+	layoutClassic layoutKind = iota
+	layoutWide
+)
+
+func TestFlag_Get(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{
+				{
+					Name:       "flag-checkout",
+					Enabled:    true,
+					Strategies: []api.Strategy{{Id: 1, Name: "default"}},
+				},
+				{
+					Name:       "flag-layout",
+					Enabled:    true,
+					Strategies: []api.Strategy{{Id: 1, Name: "default"}},
+					Variants: []api.VariantInternal{
+						{Variant: api.Variant{Name: "wide"}, Weight: 1000},
+					},
+				},
+				{
+					Name:       "flag-unmapped",
+					Enabled:    true,
+					Strategies: []api.Strategy{{Id: 1, Name: "default"}},
+					Variants: []api.VariantInternal{
+						{Variant: api.Variant{Name: "narrow"}, Weight: 1000},
+					},
+				},
+			},
+		})
+
+	withDeclaredFlags(t)
+	checkout := BoolFlag("flag-checkout", false)
+	missing := BoolFlag("flag-missing", true)
+	layout := VariantFlag("flag-layout", map[string]layoutKind{"wide": layoutWide}, layoutClassic)
+	unmapped := VariantFlag("flag-unmapped", map[string]layoutKind{"wide": layoutWide}, layoutClassic)
+
+	assert.True(missing.Get(nil, context.Context{}), "a nil client returns the default")
+	var nilClient *Client
+	assert.True(missing.Get(nilClient, context.Context{}), "a nil *Client returns the default")
+	assert.Equal(layoutClassic, layout.Get(nilClient, context.Context{}))
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	ctx := context.Context{UserId: "123"}
+	assert.True(checkout.Get(client, ctx))
+	assert.True(missing.Get(client, ctx))
+	assert.Equal(layoutWide, layout.Get(client, ctx))
+	assert.Equal(layoutClassic, unmapped.Get(client, ctx))
+	assert.Equal([]string{"wide"}, layout.Variants())
+
+	names := []string{}
+	for _, f := range DeclaredFlags() {
+		names = append(names, f.Name())
+	}
+	assert.Equal([]string{"flag-checkout", "flag-layout", "flag-missing", "flag-unmapped"}, names)
+
+	assert.NoError(client.Close())
+}
+
+// withDeclaredFlags gives the test an empty registry of declared flags, and
+// restores the previous one when the test ends.
+func withDeclaredFlags(t *testing.T) {
+	declaredFlags.Lock()
+	saved := declaredFlags.flags
+	declaredFlags.flags = map[string]Flag{}
+	declaredFlags.Unlock()
+
+	t.Cleanup(func() {
+		declaredFlags.Lock()
+		declaredFlags.flags = saved
+		declaredFlags.Unlock()
+	})
+}
+
+func TestFlag_DuplicateDeclarations(t *testing.T) {
+	assert := assert.New(t)
+	withDeclaredFlags(t)
+
+	BoolFlag("duplicate", false)
+	second := BoolFlag("duplicate", true)
+	assert.Equal([]Flag{second}, DeclaredFlags(), "a declaration of the same kind replaces the previous one")
+
+	assert.Panics(func() {
+		VariantFlag("duplicate", map[string]layoutKind{}, layoutClassic)
+	})
+	assert.Panics(func() {
+		VariantFlag("layout", map[string]layoutKind{}, layoutClassic)
+		VariantFlag("layout", map[string]string{}, "")
+	}, "variant flags mapping to different types are different kinds")
+}
@@ -2,13 +2,16 @@ module github.com/Unleash/unleash-client-go/v4
 
 require (
 	github.com/Masterminds/semver/v3 v3.1.1
+	github.com/stretchr/testify v1.2.2
+	github.com/twmb/murmur3 v1.1.5
+	gopkg.in/h2non/gock.v1 v1.0.10
+)
+
+require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/nbio/st v0.0.0-20140626010706-e9e8d9816f32 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.1.1 // indirect
-	github.com/stretchr/testify v1.2.2
-	github.com/twmb/murmur3 v1.1.5
-	gopkg.in/h2non/gock.v1 v1.0.10
 )
 
-go 1.13
+go 1.21
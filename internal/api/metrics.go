@@ -12,8 +12,10 @@ type Bucket struct {
 	Start   time.Time              `json:"start"`
 	Stop    time.Time              `json:"stop"`
 	Toggles map[string]ToggleCount `json:"toggles"`
+	// UnknownToggles counts evaluations of toggles missing from the repository.
+	UnknownToggles map[string]int32 `json:"unknownToggles,omitempty"`
 }
 
 func (b Bucket) IsEmpty() bool {
-	return len(b.Toggles) == 0
+	return len(b.Toggles) == 0 && len(b.UnknownToggles) == 0
 }
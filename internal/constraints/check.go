@@ -1,17 +1,62 @@
 package constraints
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
 	"github.com/Unleash/unleash-client-go/v4/context"
 )
 
-// Check checks if all the constraints are fulfilled by the context.
+// ErrUnknownOperator is wrapped by the error returned for constraints using an
+// operator that is neither built in nor registered with the Checker.
+var ErrUnknownOperator = errors.New("unknown constraint operator")
+
+// Error wraps the error of a constraint that could not be checked.
+type Error struct {
+	// Constraint is the failing constraint.
+	Constraint api.Constraint
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Operator checks whether the context fulfills a constraint. Inverted is applied
+// to its result by the Checker.
+type Operator func(ctx *context.Context, constraint api.Constraint) (bool, error)
+
+// Checker checks constraints against contexts. The zero value is ready to use.
+type Checker struct {
+	// Now returns the time compared by the date operators when the context does
+	// not provide one. Defaults to time.Now.
+	Now func() time.Time
+
+	// Operators are custom operators, checked after the built-in ones.
+	Operators map[api.Operator]Operator
+}
+
+// Check checks if all the constraints are fulfilled by the context, using the
+// system time for date operators.
 func Check(ctx *context.Context, constraints []api.Constraint) (bool, error) {
-	for _, c := range constraints {
-		if ok, err := checkConstraintInvertible(ctx, c); !ok || err != nil {
-			return false, err
+	return Checker{}.Check(ctx, constraints)
+}
+
+// Check checks if all the constraints are fulfilled by the context.
+func (c Checker) Check(ctx *context.Context, constraints []api.Constraint) (bool, error) {
+	for _, constraint := range constraints {
+		if ok, err := c.checkConstraintInvertible(ctx, constraint); err != nil {
+			return false, &Error{Constraint: constraint, Err: err}
+		} else if !ok {
+			return false, nil
 		}
 	}
 
@@ -19,8 +64,8 @@ func Check(ctx *context.Context, constraints []api.Constraint) (bool, error) {
 }
 
 // checkConstraintInvertible inverts the result from checkConstraint if required.
-func checkConstraintInvertible(ctx *context.Context, constraint api.Constraint) (bool, error) {
-	ok, err := checkConstraint(ctx, constraint)
+func (c Checker) checkConstraintInvertible(ctx *context.Context, constraint api.Constraint) (bool, error) {
+	ok, err := c.checkConstraint(ctx, constraint)
 
 	if constraint.Inverted {
 		return !ok, err
@@ -30,7 +75,7 @@ func checkConstraintInvertible(ctx *context.Context, constraint api.Constraint)
 }
 
 // checkConstraint checks if a specific constraint is fulfilled by the context.
-func checkConstraint(ctx *context.Context, constraint api.Constraint) (bool, error) {
+func (c Checker) checkConstraint(ctx *context.Context, constraint api.Constraint) (bool, error) {
 	switch constraint.Operator {
 	case api.OperatorIn:
 		return operatorIn(ctx, constraint), nil
@@ -53,9 +98,9 @@ func checkConstraint(ctx *context.Context, constraint api.Constraint) (bool, err
 	case api.OperatorNumGte:
 		return operatorNumGte(ctx, constraint)
 	case api.OperatorDateBefore:
-		return operatorDateBefore(ctx, constraint)
+		return operatorDateBefore(ctx, constraint, c.now)
 	case api.OperatorDateAfter:
-		return operatorDateAfter(ctx, constraint)
+		return operatorDateAfter(ctx, constraint, c.now)
 	case api.OperatorSemverEq:
 		return operatorSemverEq(ctx, constraint)
 	case api.OperatorSemverLt:
@@ -63,6 +108,26 @@ func checkConstraint(ctx *context.Context, constraint api.Constraint) (bool, err
 	case api.OperatorSemverGt:
 		return operatorSemverGt(ctx, constraint)
 	default:
-		return false, fmt.Errorf("unknown constraint operator: %s", constraint.Operator)
+		if op, ok := c.Operators[constraint.Operator]; ok {
+			return operatorCustom(ctx, constraint, op)
+		}
+		return false, fmt.Errorf("%w: %s", ErrUnknownOperator, constraint.Operator)
+	}
+}
+
+// IsKnown reports whether the operator is built in or registered with the
+// Checker.
+func (c Checker) IsKnown(operator api.Operator) bool {
+	if IsBuiltin(operator) {
+		return true
+	}
+	_, ok := c.Operators[operator]
+	return ok
+}
+
+func (c Checker) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
 	}
+	return time.Now()
 }
@@ -0,0 +1,76 @@
+package constraints
+
+import (
+	"strings"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+var builtinOperators = map[api.Operator]bool{
+	api.OperatorIn:            true,
+	api.OperatorNotIn:         true,
+	api.OperatorStrContains:   true,
+	api.OperatorStrStartsWith: true,
+	api.OperatorStrEndsWith:   true,
+	api.OperatorNumEq:         true,
+	api.OperatorNumLt:         true,
+	api.OperatorNumLte:        true,
+	api.OperatorNumGt:         true,
+	api.OperatorNumGte:        true,
+	api.OperatorDateBefore:    true,
+	api.OperatorDateAfter:     true,
+	api.OperatorSemverEq:      true,
+	api.OperatorSemverLt:      true,
+	api.OperatorSemverGt:      true,
+}
+
+// IsBuiltin reports whether the operator is implemented by the Checker itself.
+func IsBuiltin(operator api.Operator) bool {
+	return builtinOperators[operator]
+}
+
+// operatorCustom calls a custom operator. For case insensitive constraints the
+// context value and the constraint values are lower-cased before the call, so
+// that operators do not have to handle CaseInsensitive themselves.
+func operatorCustom(ctx *context.Context, constraint api.Constraint, op Operator) (bool, error) {
+	if !constraint.CaseInsensitive {
+		return op(ctx, constraint)
+	}
+
+	lowered := withField(*ctx, constraint.ContextName, strings.ToLower(ctx.Field(constraint.ContextName)))
+	constraint.Value = strings.ToLower(constraint.Value)
+	values := make([]string, len(constraint.Values))
+	for i, v := range constraint.Values {
+		values[i] = strings.ToLower(v)
+	}
+	constraint.Values = values
+	return op(&lowered, constraint)
+}
+
+// withField returns a copy of the context with the named field set, mirroring
+// the lookup of context.Context.Field.
+func withField(ctx context.Context, name, value string) context.Context {
+	switch name {
+	case "userId":
+		ctx.UserId = value
+	case "sessionId":
+		ctx.SessionId = value
+	case "remoteAddress":
+		ctx.RemoteAddress = value
+	case "environment":
+		ctx.Environment = value
+	case "appName":
+		ctx.AppName = value
+	case "currentTime":
+		ctx.CurrentTime = value
+	default:
+		props := make(map[string]string, len(ctx.Properties)+1)
+		for k, v := range ctx.Properties {
+			props[k] = v
+		}
+		props[name] = value
+		ctx.Properties = props
+	}
+	return ctx
+}
@@ -0,0 +1,66 @@
+package constraints
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperatorCustom(t *testing.T) {
+	regexMatch := func(ctx *context.Context, constraint api.Constraint) (bool, error) {
+		re, err := regexp.Compile(constraint.Value)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(ctx.Field(constraint.ContextName)), nil
+	}
+	checker := Checker{Operators: map[api.Operator]Operator{"REGEX_MATCH": regexMatch}}
+
+	testCases := []checkTestCase{
+		{
+			ctx:         &context.Context{UserId: "user-42"},
+			constraints: []api.Constraint{{ContextName: "userId", Operator: "REGEX_MATCH", Value: "^user-[0-9]+$"}},
+			expected:    true,
+		},
+		{
+			ctx:         &context.Context{UserId: "admin"},
+			constraints: []api.Constraint{{ContextName: "userId", Operator: "REGEX_MATCH", Value: "^user-[0-9]+$"}},
+			expected:    false,
+		},
+		{
+			ctx:         &context.Context{UserId: "admin"},
+			constraints: []api.Constraint{{ContextName: "userId", Operator: "REGEX_MATCH", Value: "^user-[0-9]+$", Inverted: true}},
+			expected:    true,
+		},
+		{
+			ctx:         &context.Context{Properties: map[string]string{"email": "Jane@Example.com"}},
+			constraints: []api.Constraint{{ContextName: "email", Operator: "REGEX_MATCH", Value: "@EXAMPLE\\.com$", CaseInsensitive: true}},
+			expected:    true,
+		},
+		{
+			ctx:         &context.Context{Properties: map[string]string{"email": "Jane@Example.com"}},
+			constraints: []api.Constraint{{ContextName: "email", Operator: "REGEX_MATCH", Value: "@EXAMPLE\\.com$"}},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		ok, err := checker.Check(tc.ctx, tc.constraints)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, ok)
+	}
+
+	failing := Checker{Operators: map[api.Operator]Operator{
+		"FAILING": func(*context.Context, api.Constraint) (bool, error) { return false, errors.New("failed") },
+	}}
+	ok, err := failing.Check(&context.Context{}, []api.Constraint{{Operator: "FAILING", Inverted: true}})
+	assert.False(t, ok)
+	assert.EqualError(t, err, "failed")
+
+	assert.True(t, IsBuiltin(api.OperatorIn))
+	assert.False(t, IsBuiltin("REGEX_MATCH"))
+}
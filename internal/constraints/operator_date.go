@@ -6,14 +6,14 @@ import (
 	"time"
 )
 
-func operatorDateBefore(ctx *context.Context, constraint api.Constraint) (bool, error) {
-	return operatorDate(ctx, constraint, func(context time.Time, constraint time.Time) bool {
+func operatorDateBefore(ctx *context.Context, constraint api.Constraint, now func() time.Time) (bool, error) {
+	return operatorDate(ctx, constraint, now, func(context time.Time, constraint time.Time) bool {
 		return context.Before(constraint)
 	})
 }
 
-func operatorDateAfter(ctx *context.Context, constraint api.Constraint) (bool, error) {
-	return operatorDate(ctx, constraint, func(context time.Time, constraint time.Time) bool {
+func operatorDateAfter(ctx *context.Context, constraint api.Constraint, now func() time.Time) (bool, error) {
+	return operatorDate(ctx, constraint, now, func(context time.Time, constraint time.Time) bool {
 		return context.After(constraint)
 	})
 }
@@ -21,9 +21,10 @@ func operatorDateAfter(ctx *context.Context, constraint api.Constraint) (bool, e
 func operatorDate(
 	ctx *context.Context,
 	constraint api.Constraint,
+	now func() time.Time,
 	check func(context time.Time, constraint time.Time) bool,
 ) (bool, error) {
-	contextParsed, contextErr := contextDateValueOrNow(ctx, constraint)
+	contextParsed, contextErr := contextDateValueOrNow(ctx, constraint, now)
 	constraintParsed, constraintErr := time.Parse(time.RFC3339, constraint.Value)
 
 	if contextErr != nil {
@@ -37,12 +38,12 @@ func operatorDate(
 	return check(contextParsed, constraintParsed), nil
 }
 
-func contextDateValueOrNow(ctx *context.Context, constraint api.Constraint) (time.Time, error) {
+func contextDateValueOrNow(ctx *context.Context, constraint api.Constraint, now func() time.Time) (time.Time, error) {
 	contextValue := ctx.Field(constraint.ContextName)
 
 	if contextValue != "" {
 		return time.Parse(time.RFC3339, contextValue)
 	}
 
-	return time.Now(), nil
+	return now(), nil
 }
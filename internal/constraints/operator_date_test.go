@@ -100,3 +100,17 @@ func TestOperatorDate(t *testing.T) {
 		}
 	}
 }
+
+func TestOperatorDate_CheckerNow(t *testing.T) {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	checker := Checker{Now: func() time.Time { return now }}
+	ctx := &context.Context{}
+
+	ok, err := checker.Check(ctx, []api.Constraint{{ContextName: "currentTime", Operator: "DATE_AFTER", Value: "2006-01-02T15:04:04Z"}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = checker.Check(ctx, []api.Constraint{{ContextName: "currentTime", Operator: "DATE_BEFORE", Value: "2006-01-02T15:04:04Z"}})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -18,8 +18,14 @@ type flexibleRolloutStrategy struct {
 
 // NewFlexibleRolloutStrategy creates a new instance of the flexible rollout strategy.
 func NewFlexibleRolloutStrategy() *flexibleRolloutStrategy {
+	return NewSeededFlexibleRolloutStrategy(timeSeed())
+}
+
+// NewSeededFlexibleRolloutStrategy creates a new instance of the flexible rollout
+// strategy whose random stickiness is derived from the seed.
+func NewSeededFlexibleRolloutStrategy(seed int64) *flexibleRolloutStrategy {
 	s := &flexibleRolloutStrategy{
-		random: newRng(),
+		random: newRng(seed),
 	}
 	return s
 }
@@ -40,10 +46,7 @@ func (s flexibleRolloutStrategy) resolveStickiness(st stickiness, ctx context.Co
 }
 
 func (s flexibleRolloutStrategy) IsEnabled(params map[string]interface{}, ctx *context.Context) bool {
-	groupID := ""
-	if gID, found := params[strategy.ParamGroupId]; found {
-		groupID = gID.(string)
-	}
+	groupID, _ := params[strategy.ParamGroupId].(string)
 
 	rollout, found := params[strategy.ParamRollout]
 	if !found {
@@ -55,7 +58,8 @@ func (s flexibleRolloutStrategy) IsEnabled(params map[string]interface{}, ctx *c
 		return false
 	}
 
-	sticky := coalesce(params[strategy.ParamStickiness].(string), string(defaultStickiness))
+	sticky, _ := params[strategy.ParamStickiness].(string)
+	sticky = coalesce(sticky, string(defaultStickiness))
 	stickinessID := s.resolveStickiness(stickiness(sticky), *ctx)
 
 	if stickinessID == "" {
@@ -33,3 +33,15 @@ func TestFlexibleRolloutStrategy_IsWellDistributed(t *testing.T) {
 
 	assert.InDelta(t, 50, actualPercentage, 1.0)
 }
+
+func TestFlexibleRolloutStrategy_InvalidParameters(t *testing.T) {
+	s := NewFlexibleRolloutStrategy()
+	ctx := &context.Context{UserId: "123"}
+
+	assert.NotPanics(t, func() {
+		s.IsEnabled(map[string]interface{}{strategy.ParamRollout: 100}, ctx)
+		s.IsEnabled(map[string]interface{}{strategy.ParamRollout: 100, strategy.ParamStickiness: 1.0}, ctx)
+		s.IsEnabled(map[string]interface{}{strategy.ParamRollout: 100, strategy.ParamGroupId: 1.0}, ctx)
+	})
+	assert.True(t, s.IsEnabled(map[string]interface{}{strategy.ParamRollout: 100}, ctx), "missing stickiness defaults to default stickiness")
+}
@@ -10,8 +10,14 @@ type gradualRolloutRandomStrategy struct {
 }
 
 func NewGradualRolloutRandomStrategy() *gradualRolloutRandomStrategy {
+	return NewSeededGradualRolloutRandomStrategy(timeSeed())
+}
+
+// NewSeededGradualRolloutRandomStrategy creates a new instance of the gradual
+// rollout random strategy whose random numbers are derived from the seed.
+func NewSeededGradualRolloutRandomStrategy(seed int64) *gradualRolloutRandomStrategy {
 	s := &gradualRolloutRandomStrategy{
-		newRng(),
+		newRng(seed),
 	}
 	return s
 }
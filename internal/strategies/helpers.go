@@ -98,7 +98,11 @@ func (r *rng) string() string {
 
 // newRng creates a new random number generator for numbers between 1-100
 // and uses a mutex internally to ensure safe concurrent reads.
-func newRng() *rng {
-	seed := time.Now().UnixNano() + int64(os.Getpid())
+func newRng(seed int64) *rng {
 	return &rng{random: rand.New(rand.NewSource(seed))}
 }
+
+// timeSeed returns a seed that differs between processes and calls.
+func timeSeed() int64 {
+	return time.Now().UnixNano() + int64(os.Getpid())
+}
@@ -70,7 +70,7 @@ func TestCoalesce(t *testing.T) {
 }
 
 func TestNewRng(t *testing.T) {
-	rng := newRng()
+	rng := newRng(timeSeed())
 
 	wg := sync.WaitGroup{}
 
@@ -125,3 +125,10 @@ func BenchmarkNormalizedValue(b *testing.B) {
 		}
 	})
 }
+
+func TestNewRng_Seeded(t *testing.T) {
+	a, b := newRng(42), newRng(42)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, a.string(), b.string())
+	}
+}
@@ -0,0 +1,131 @@
+package unleash
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+)
+
+// discardLogger is used when no logger is configured, so that the client never
+// writes to the standard output on its own.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// loggerOrDiscard returns the logger, or a logger discarding everything if it is
+// nil.
+func loggerOrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return discardLogger
+	}
+	return logger
+}
+
+// errorAttrs returns the structured attributes describing an error reported by
+// the client.
+func errorAttrs(err error) []any {
+	attrs := []any{slog.String("error", err.Error())}
+
+	var fetchErr *FetchError
+	var constraintErr *ConstraintError
+	var segmentErr *SegmentNotFoundError
+	var strategyErr *StrategyNotFoundError
+	var invalidErr *api.InvalidFeatureError
+	var staleErr *StaleDataError
+	switch {
+	case errors.As(err, &fetchErr):
+		attrs = append(attrs,
+			slog.String("method", fetchErr.Method),
+			slog.String("url", fetchErr.URL),
+			slog.Int("status_code", fetchErr.StatusCode))
+	case errors.As(err, &constraintErr):
+		attrs = append(attrs,
+			slog.String("feature", constraintErr.Feature),
+			slog.Int("strategy_id", constraintErr.StrategyId),
+			slog.String("context_field", constraintErr.Constraint.ContextName),
+			slog.String("operator", string(constraintErr.Constraint.Operator)))
+	case errors.As(err, &segmentErr):
+		attrs = append(attrs,
+			slog.String("feature", segmentErr.Feature),
+			slog.Int("strategy_id", segmentErr.StrategyId),
+			slog.Int("segment_id", segmentErr.SegmentId))
+	case errors.As(err, &strategyErr):
+		attrs = append(attrs,
+			slog.String("feature", strategyErr.Feature),
+			slog.Int("strategy_id", strategyErr.StrategyId),
+			slog.String("strategy", strategyErr.Strategy))
+	case errors.As(err, &invalidErr):
+		attrs = append(attrs,
+			slog.String("feature", invalidErr.Feature),
+			slog.String("path", invalidErr.Path))
+	case errors.As(err, &staleErr):
+		attrs = append(attrs,
+			slog.Duration("age", staleErr.Age),
+			slog.Duration("max_age", staleErr.MaxAge))
+	}
+	return attrs
+}
+
+// SlogListener is an implementation of all of the listener interfaces that logs
+// the events of the client with a structured logger. It replaces DebugListener.
+// Errors and warnings are logged at their own level, the readiness, registration
+// and updates at the info level, and the counts and sent metrics at the debug
+// level.
+type SlogListener struct {
+	// Logger receives the events. If nil, slog.Default() is used.
+	Logger *slog.Logger
+}
+
+func (l SlogListener) logger() *slog.Logger {
+	if l.Logger == nil {
+		return slog.Default()
+	}
+	return l.Logger
+}
+
+// OnError logs errors.
+func (l SlogListener) OnError(err error) {
+	l.logger().Error("unleash error", errorAttrs(err)...)
+}
+
+// OnWarning logs warnings.
+func (l SlogListener) OnWarning(warning error) {
+	l.logger().Warn("unleash warning", errorAttrs(warning)...)
+}
+
+// OnReady logs when the repository is ready.
+func (l SlogListener) OnReady() {
+	l.logger().Info("unleash ready")
+}
+
+// OnCount logs when the feature is queried.
+func (l SlogListener) OnCount(name string, enabled bool) {
+	l.logger().Debug("unleash feature queried", slog.String("feature", name), slog.Bool("enabled", enabled))
+}
+
+// OnSent logs when the metrics have been sent.
+func (l SlogListener) OnSent(payload MetricsData) {
+	l.logger().Debug("unleash metrics sent",
+		slog.String("app_name", payload.AppName),
+		slog.String("instance_id", payload.InstanceID),
+		slog.Int("features", len(payload.Bucket.Toggles)))
+}
+
+// OnRegistered logs when the client has registered.
+func (l SlogListener) OnRegistered(payload ClientData) {
+	l.logger().Info("unleash client registered",
+		slog.String("app_name", payload.AppName),
+		slog.String("instance_id", payload.InstanceID),
+		slog.Any("strategies", payload.Strategies))
+}
+
+// OnUpdate logs when the feature toggles changed.
+func (l SlogListener) OnUpdate(diff RepositoryDiff) {
+	l.logger().Info("unleash feature toggles updated",
+		slog.Any("added", diff.Added),
+		slog.Any("removed", diff.Removed),
+		slog.Any("enabled", diff.Enabled),
+		slog.Any("disabled", diff.Disabled),
+		slog.Any("strategies_changed", diff.StrategiesChanged),
+		slog.Any("variants_changed", diff.VariantsChanged))
+}
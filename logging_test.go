@@ -0,0 +1,93 @@
+package unleash
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// logRecords decodes the records written by a JSON handler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var r map[string]interface{}
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(503)
+
+	var buf bytes.Buffer
+	errs := errorCollector{errors: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(errs),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+	assert.NoError(err)
+	<-errs.errors
+	assert.NoError(client.Close())
+
+	records := logRecords(t, &buf)
+	if assert.Len(records, 1) {
+		assert.Equal("ERROR", records[0]["level"])
+		assert.Equal(mockerServer+"/client/features", records[0]["url"])
+		assert.Equal(float64(503), records[0]["status_code"])
+	}
+}
+
+func TestBootstrapStorage_LogsLoadErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	storage := &BootstrapStorage{
+		Reader: strings.NewReader("not json"),
+		Logger: slog.New(slog.NewJSONHandler(&buf, nil)),
+	}
+	storage.Init(t.TempDir(), mockAppName)
+
+	records := logRecords(t, &buf)
+	if assert.Len(records, 1) {
+		assert.Equal("could not load bootstrap storage", records[0]["msg"])
+		assert.Equal(mockAppName, records[0]["app_name"])
+	}
+}
+
+func TestSlogListener(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	listener := SlogListener{Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	listener.OnWarning(&StrategyNotFoundError{Feature: "feature", StrategyId: 1, Strategy: "custom"})
+	listener.OnCount("feature", true)
+	listener.OnUpdate(RepositoryDiff{Added: []string{"feature"}})
+
+	records := logRecords(t, &buf)
+	if assert.Len(records, 3) {
+		assert.Equal("WARN", records[0]["level"])
+		assert.Equal("feature", records[0]["feature"])
+		assert.Equal("custom", records[0]["strategy"])
+		assert.Equal("DEBUG", records[1]["level"])
+		assert.Equal(true, records[1]["enabled"])
+		assert.Equal([]interface{}{"feature"}, records[2]["added"])
+	}
+}
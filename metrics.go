@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Unleash/unleash-client-go/v4/clock"
 	"github.com/Unleash/unleash-client-go/v4/internal/api"
 )
 
@@ -62,7 +63,7 @@ type metrics struct {
 	started  time.Time
 	bucketMu sync.Mutex
 	bucket   api.Bucket
-	ticker   *time.Ticker
+	ticker   clock.Ticker
 	close    chan struct{}
 	closed   chan struct{}
 	ctx      context.Context
@@ -70,13 +71,17 @@ type metrics struct {
 	maxSkips float64
 	errors   float64
 	skips    float64
+
+	// stateMu guards the backoff and the registration state.
+	stateMu      sync.Mutex
+	isRegistered bool
 }
 
 func newMetrics(options metricsOptions, channels metricsChannels) *metrics {
 	m := &metrics{
 		metricsChannels: channels,
 		options:         options,
-		started:         time.Now(),
+		started:         options.clock.Now(),
 		close:           make(chan struct{}),
 		closed:          make(chan struct{}),
 		maxSkips:        10,
@@ -96,7 +101,7 @@ func newMetrics(options metricsOptions, channels metricsChannels) *metrics {
 		m.options.disableMetrics = true
 	}
 	if !m.options.disableMetrics {
-		m.ticker = time.NewTicker(m.options.metricsInterval)
+		m.ticker = m.options.clock.NewTicker(m.options.metricsInterval)
 		m.registerInstance()
 		go m.sync()
 	}
@@ -114,10 +119,30 @@ func (m *metrics) Close() error {
 	return nil
 }
 
+// Shutdown stops sending metrics periodically and sends the pending bucket. A
+// request still in flight when the context is done is cancelled.
+func (m *metrics) Shutdown(ctx context.Context) error {
+	if m.options.disableMetrics {
+		return nil
+	}
+	stop := context.AfterFunc(ctx, m.cancel)
+	defer stop()
+
+	m.ticker.Stop()
+	close(m.close)
+	<-m.closed
+	err := m.sendMetrics()
+	m.cancel()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
 func (m *metrics) sync() {
 	for {
 		select {
-		case <-m.ticker.C:
+		case <-m.ticker.C():
 			if m.skips == 0 {
 				m.sendMetrics()
 			} else {
@@ -143,36 +168,62 @@ func (m *metrics) registerInstance() {
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode > http.StatusMultipleChoices {
 		m.warn(fmt.Errorf("%s return %d", u.String(), resp.StatusCode))
+		return
 	}
 
+	m.stateMu.Lock()
+	m.isRegistered = true
+	m.stateMu.Unlock()
 	m.registered <- payload
 }
 func (m *metrics) backoff() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
 	m.errors = math.Min(m.maxSkips, m.errors+1)
 	m.skips = m.errors
 }
 
 func (m *metrics) configurationError() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
 	m.errors = m.maxSkips
 	m.skips = m.errors
 }
 
 func (m *metrics) successfulPost() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
 	m.errors = math.Max(0, m.errors-1)
 	m.skips = m.errors
 }
 
 func (m *metrics) decrementSkip() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
 	m.skips = math.Max(0, m.skips-1)
 }
-func (m *metrics) sendMetrics() {
+
+// status describes the reporting of metrics.
+func (m *metrics) status() MetricsStatus {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	return MetricsStatus{
+		Disabled:   m.options.disableMetrics,
+		Registered: m.isRegistered,
+		Backoff:    BackoffStatus{Failures: int(m.errors), Skips: int(m.skips)},
+	}
+}
+
+// sendMetrics sends the current bucket and returns the error it reported, if
+// any. If the server rejected the bucket its counts are kept for the next one.
+func (m *metrics) sendMetrics() error {
 	m.bucketMu.Lock()
 	bucket := m.resetBucket()
 	m.bucketMu.Unlock()
 	if bucket.IsEmpty() {
-		return
+		return nil
 	}
-	bucket.Stop = time.Now()
+	bucket.Stop = m.options.clock.Now()
 	payload := MetricsData{
 		AppName:    m.options.appName,
 		InstanceID: m.options.instanceId,
@@ -183,7 +234,7 @@ func (m *metrics) sendMetrics() {
 	resp, err := m.doPost(u, payload)
 	if err != nil {
 		m.err(err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -193,23 +244,32 @@ func (m *metrics) sendMetrics() {
 		} else if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
 			m.backoff()
 		}
-		m.warn(fmt.Errorf("%s return %d", u.String(), resp.StatusCode))
-		// The post failed, re-add the metrics we attempted to send so
-		// they are included in the next post.
-		for name, tc := range bucket.Toggles {
-			m.add(name, true, tc.Yes)
-			m.add(name, false, tc.No)
-		}
+		err := fmt.Errorf("%s return %d", u.String(), resp.StatusCode)
+		m.warn(err)
+		m.restoreBucket(bucket)
+		return err
+	}
+	m.successfulPost()
+	m.sent.push(payload)
+	return nil
+}
 
-		m.bucketMu.Lock()
-		// Set the start time of the current bucket to the one we
-		// attempted to send.
-		m.bucket.Start = bucket.Start
-		m.bucketMu.Unlock()
-	} else {
-		m.successfulPost()
-		m.sent <- payload
+// restoreBucket re-adds the metrics of a bucket that could not be sent, so that
+// they are included in the next post.
+func (m *metrics) restoreBucket(bucket api.Bucket) {
+	for name, tc := range bucket.Toggles {
+		m.add(name, true, tc.Yes)
+		m.add(name, false, tc.No)
+	}
+
+	m.bucketMu.Lock()
+	for name, n := range bucket.UnknownToggles {
+		m.bucket.UnknownToggles[name] += n
 	}
+	// Set the start time of the current bucket to the one we
+	// attempted to send.
+	m.bucket.Start = bucket.Start
+	m.bucketMu.Unlock()
 }
 
 func (m *metrics) doPost(url *url.URL, payload interface{}) (*http.Response, error) {
@@ -261,7 +321,7 @@ func (m *metrics) count(name string, enabled bool) {
 		return
 	}
 	m.add(name, enabled, 1)
-	m.metricsChannels.count <- metric{Name: name, Enabled: enabled}
+	m.metricsChannels.count.push(metric{Name: name, Enabled: enabled})
 }
 
 func (m *metrics) countVariants(name string, enabled bool, variantName string) {
@@ -270,7 +330,7 @@ func (m *metrics) countVariants(name string, enabled bool, variantName string) {
 	}
 
 	m.add(name, enabled, 1)
-	m.metricsChannels.count <- metric{Name: name, Enabled: enabled}
+	m.metricsChannels.count.push(metric{Name: name, Enabled: enabled})
 
 	m.bucketMu.Lock()
 	defer m.bucketMu.Unlock()
@@ -288,11 +348,24 @@ func (m *metrics) countVariants(name string, enabled bool, variantName string) {
 	m.bucket.Toggles[name] = t
 }
 
+// countUnknown counts an evaluation of a feature toggle that is missing from the
+// repository.
+func (m *metrics) countUnknown(name string) {
+	if m.options.disableMetrics {
+		return
+	}
+
+	m.bucketMu.Lock()
+	defer m.bucketMu.Unlock()
+	m.bucket.UnknownToggles[name]++
+}
+
 func (m *metrics) resetBucket() api.Bucket {
 	prev := m.bucket
 	m.bucket = api.Bucket{
-		Start:   time.Now(),
-		Toggles: map[string]api.ToggleCount{},
+		Start:          m.options.clock.Now(),
+		Toggles:        map[string]api.ToggleCount{},
+		UnknownToggles: map[string]int32{},
 	}
 	return prev
 }
@@ -426,3 +426,71 @@ func TestMetrics_ErrorCountShouldDecreaseIfSuccessful(t *testing.T) {
 	assert.Equal(float64(0), client.metrics.errors)
 	assert.Nil(err, "Client should close without a problem")
 }
+
+func TestMetrics_CountsUnknownToggles(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Post("/client/register").
+		Reply(200)
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		JSON(api.FeatureResponse{})
+
+	mockListener := &MockedListener{}
+	mockListener.On("OnReady").Return()
+	mockListener.On("OnCount", "unknown", false).Return()
+	mockListener.On("OnRegistered", mock.AnythingOfType("ClientData"))
+
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithListener(mockListener),
+	)
+	assert.Nil(err, "client should not return an error")
+
+	client.WaitForReady()
+	client.IsEnabled("unknown")
+	client.GetVariant("unknown")
+
+	client.metrics.bucketMu.Lock()
+	assert.EqualValues(2, client.metrics.bucket.UnknownToggles["unknown"])
+	client.metrics.bucketMu.Unlock()
+	client.Close()
+}
+
+func TestMetrics_DoesNotCountUnknownTogglesBeforeReady(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/client/features" {
+			<-release
+			writeJSON(rw, api.FeatureResponse{})
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithMetricsInterval(time.Hour),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+
+	client.IsEnabled("unknown")
+	close(release)
+	client.WaitForReady()
+	client.IsEnabled("unknown")
+
+	client.metrics.bucketMu.Lock()
+	assert.EqualValues(1, client.metrics.bucket.UnknownToggles["unknown"])
+	client.metrics.bucketMu.Unlock()
+	assert.NoError(client.Close())
+}
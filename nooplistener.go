@@ -26,3 +26,7 @@ func (l NoopListener) OnSent(payload MetricsData) {
 // The client has registered.
 func (l NoopListener) OnRegistered(payload ClientData) {
 }
+
+// The feature toggles changed.
+func (l NoopListener) OnUpdate(diff RepositoryDiff) {
+}
@@ -20,5 +20,5 @@ func Test_defaultsToNoopListener(t *testing.T) {
 	res := IsEnabled("test", WithFallback(false))
 	assert.Equal(t, false, res)
 
-	assert.IsType(t, &NoopListener{}, defaultClient.errorListener)
+	assert.IsType(t, &NoopListener{}, defaultClient.options.listener)
 }
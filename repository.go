@@ -2,21 +2,26 @@ package unleash
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
 )
 
 var SEGMENT_CLIENT_SPEC_VERSION = "4.3.1"
 
+// repository fetches the feature toggles and holds them for the clients attached
+// to it through a repositoryView. It is closed when the last reference to it is
+// released.
 type repository struct {
-	repositoryChannels
 	sync.RWMutex
 	options       repositoryOptions
 	etag          string
@@ -25,24 +30,63 @@ type repository struct {
 	ctx           context.Context
 	cancel        func()
 	isReady       bool
-	refreshTicker *time.Ticker
-	segments      map[int][]api.Constraint
+	refreshTicker clock.Ticker
+	segments      []api.Segment
+	views         map[*repositoryView]struct{}
+	reported      map[string]bool
 	errors        float64
 	maxSkips      float64
 	skips         float64
+
+	// refMu guards the reference count.
+	refMu sync.Mutex
+	refs  int
+
+	// stateMu guards the backoff and the fields below, which describe the
+	// repository in its status.
+	stateMu     sync.Mutex
+	source      DataSource
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastStatus  int
+	lastError   string
+
+	// freshSince is the time of the last successful fetch, the time the backup
+	// was saved, or zero when the age of the data is unknown. It is only used by
+	// the sync goroutine.
+	freshSince time.Time
+	stale      atomic.Bool
+
+	// persistErr is the error of persisting the storage when the repository
+	// closed. It is set before closed is closed.
+	persistErr error
 }
 
-func newRepository(options repositoryOptions, channels repositoryChannels) *repository {
+// repositoryView is the part of a repository seen by one client: the evaluator
+// built with the strategies of the client over the shared feature toggles, and
+// the channels of the client receiving the events of the repository.
+type repositoryView struct {
+	repositoryChannels
+	repo     *repository
+	options  []evaluator.Option
+	eval     *evaluator.Evaluator
+	reported map[string]bool
+}
+
+// newRepository creates a repository holding one reference. Its sync goroutine is
+// started by start.
+func newRepository(options repositoryOptions) *repository {
 	repo := &repository{
-		options:            options,
-		repositoryChannels: channels,
-		close:              make(chan struct{}),
-		closed:             make(chan struct{}),
-		refreshTicker:      time.NewTicker(options.refreshInterval),
-		segments:           map[int][]api.Constraint{},
-		errors:             0,
-		maxSkips:           10,
-		skips:              0,
+		options:       options,
+		close:         make(chan struct{}),
+		closed:        make(chan struct{}),
+		refreshTicker: options.clock.NewTicker(options.refreshInterval),
+		views:         map[*repositoryView]struct{}{},
+		reported:      map[string]bool{},
+		errors:        0,
+		maxSkips:      10,
+		skips:         0,
+		refs:          1,
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	repo.ctx = ctx
@@ -56,66 +100,249 @@ func newRepository(options repositoryOptions, channels repositoryChannels) *repo
 		repo.options.storage = &DefaultStorage{}
 	}
 
-	repo.options.storage.Init(options.backupPath, options.appName)
+	if options.logger == nil {
+		repo.options.logger = discardLogger
+	}
 
-	go repo.sync()
+	repo.options.storage.Init(options.backupPath, options.appName)
+	repo.source = initialSource(repo.options.storage)
+	repo.freshSince = initialFreshness(repo.options.storage, repo.source, options.clock.Now())
 
 	return repo
 }
 
+// start starts fetching the feature toggles.
+func (r *repository) start() {
+	go r.sync()
+}
+
+// acquire takes a reference to the repository, unless it was closed.
+func (r *repository) acquire() error {
+	r.refMu.Lock()
+	defer r.refMu.Unlock()
+	if r.refs == 0 {
+		return errors.New("the shared repository is closed")
+	}
+	r.refs++
+	return nil
+}
+
+// release drops a reference to the repository and closes it when it was the
+// last one, returning the error of persisting the storage.
+func (r *repository) release() error {
+	r.refMu.Lock()
+	defer r.refMu.Unlock()
+	if r.refs == 0 {
+		return nil
+	}
+	r.refs--
+	if r.refs == 0 {
+		return r.Close()
+	}
+	return nil
+}
+
+// attach creates the view of a client evaluating the feature toggles with the
+// given options and receiving the events on the given channels. If the
+// repository is already ready, the view is signaled right away.
+func (r *repository) attach(options []evaluator.Option, channels repositoryChannels) *repositoryView {
+	v := &repositoryView{
+		repositoryChannels: channels,
+		repo:               r,
+		options:            options,
+		reported:           map[string]bool{},
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	v.refresh(r.listFeatures(), r.segments)
+	r.views[v] = struct{}{}
+	if r.isReady {
+		v.ready <- true
+	}
+	return v
+}
+
+// detach removes the view of a client and releases its reference, closing the
+// repository if it was the last one. The view still receives the events of the
+// repository while it closes.
+func (r *repository) detach(v *repositoryView) error {
+	err := r.release()
+	r.Lock()
+	delete(r.views, v)
+	r.Unlock()
+	return err
+}
+
+// viewList returns the attached views.
+func (r *repository) viewList() []*repositoryView {
+	r.RLock()
+	defer r.RUnlock()
+	views := make([]*repositoryView, 0, len(r.views))
+	for v := range r.views {
+		views = append(views, v)
+	}
+	return views
+}
+
+func (r *repository) err(err error) {
+	r.options.logger.Error("unleash error", errorAttrs(err)...)
+	for _, v := range r.viewList() {
+		v.errors.push(err)
+	}
+}
+
+func (r *repository) warn(err error) {
+	r.options.logger.Warn("unleash warning", errorAttrs(err)...)
+	for _, v := range r.viewList() {
+		v.warnings.push(err)
+	}
+}
+
+// publish delivers an event to the attached views.
+func (r *repository) publish(e Event) {
+	for _, v := range r.viewList() {
+		v.events.push(e)
+	}
+}
+
+// initialSource tells where the data held by the storage after its initialization
+// comes from.
+func initialSource(storage Storage) DataSource {
+	if len(storage.List()) == 0 {
+		return DataSourceNone
+	}
+	if bs, ok := storage.(*BootstrapStorage); ok && bs.bootstrapped {
+		return DataSourceBootstrap
+	}
+	return DataSourceBackup
+}
+
+// initialFreshness returns the time the data held by the storage after its
+// initialization was fresh. An empty storage holds nothing to be stale yet, while
+// the data of a bootstrap or of a storage not telling when it was saved is
+// treated as stale until a fetch succeeds.
+func initialFreshness(storage Storage, source DataSource, now time.Time) time.Time {
+	if source == DataSourceNone {
+		return now
+	}
+	if ds, ok := storage.(datedStorage); ok {
+		return ds.savedAt()
+	}
+	return time.Time{}
+}
+
 func (r *repository) fetchAndReportError() {
+	attempt := r.options.clock.Now()
 	err := r.fetch()
+
+	r.stateMu.Lock()
+	r.lastAttempt = attempt
+	if err == nil {
+		r.lastSuccess = r.options.clock.Now()
+		r.lastError = ""
+		r.source = DataSourceServer
+		r.freshSince = r.lastSuccess
+	} else {
+		r.lastError = err.Error()
+	}
+	r.stateMu.Unlock()
+
 	if err != nil {
 		if urlErr, ok := err.(*url.Error); !(ok && urlErr.Err == context.Canceled) {
 			r.err(err)
 		}
 	}
-	if !r.isReady && err == nil {
-		r.isReady = true
-		r.ready <- true
+	if err == nil {
+		r.Lock()
+		if !r.isReady {
+			r.isReady = true
+			for v := range r.views {
+				v.ready <- true
+			}
+		}
+		r.Unlock()
 	}
 }
 
 func (r *repository) sync() {
 	r.fetchAndReportError()
+	r.checkStaleness()
 	for {
 		select {
 		case <-r.close:
 			if err := r.options.storage.Persist(); err != nil {
+				r.persistErr = err
 				r.err(err)
 			}
 			close(r.closed)
 			return
-		case <-r.refreshTicker.C:
+		case <-r.refreshTicker.C():
 			if r.skips == 0 {
 				r.fetchAndReportError()
 			} else {
 				r.decrementSkips()
 			}
+			r.checkStaleness()
 		}
 	}
 }
 
+// checkStaleness compares the age of the data with the maximum age, and reports
+// when the data becomes stale.
+func (r *repository) checkStaleness() {
+	if r.options.maxDataAge <= 0 {
+		return
+	}
+	var age time.Duration
+	if !r.freshSince.IsZero() {
+		age = r.options.clock.Now().Sub(r.freshSince)
+		if age <= r.options.maxDataAge {
+			r.stale.Store(false)
+			return
+		}
+	}
+	if !r.stale.Swap(true) {
+		r.stateMu.Lock()
+		lastSuccess := r.lastSuccess
+		r.stateMu.Unlock()
+		r.warn(&StaleDataError{Age: age, MaxAge: r.options.maxDataAge, LastFetchSuccess: lastSuccess})
+	}
+}
+
+// isStale reports whether the data is older than the maximum age.
+func (r *repository) isStale() bool {
+	return r.stale.Load()
+}
+
 func (r *repository) backoff() {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
 	r.errors = math.Min(r.maxSkips, r.errors+1)
 	r.skips = r.errors
 }
 
 func (r *repository) successfulFetch() {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
 	r.errors = math.Max(0, r.errors-1)
 	r.skips = r.errors
 }
 
 func (r *repository) decrementSkips() {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
 	r.skips = math.Max(0, r.skips-1)
 }
 func (r *repository) configurationError() {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
 	r.errors = r.maxSkips
 	r.skips = r.errors
 }
 
 func (r *repository) fetch() error {
-	u, _ := r.options.url.Parse(getFetchURLPath(r.options.projectName))
+	u, _ := r.options.url.Parse(getFetchURLPath(r.options.projects, r.options.namePrefix, r.options.tags))
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
@@ -145,73 +372,158 @@ func (r *repository) fetch() error {
 
 	defer resp.Body.Close()
 
+	r.stateMu.Lock()
+	r.lastStatus = resp.StatusCode
+	r.stateMu.Unlock()
+
 	if resp.StatusCode == http.StatusNotModified {
+		// A successful fetch makes the data fresh, before the handlers of the
+		// event evaluate it.
+		r.stale.Store(false)
+		r.publish(FetchedEvent{NotModified: true, ETag: r.etag})
 		return nil
 	}
 	if err := r.statusIsOK(resp); err != nil {
 		return err
 	}
 
-	var featureResp api.FeatureResponse
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&featureResp); err != nil {
+	featureResp, invalid, err := api.DecodeFeatureResponse(resp.Body)
+	if err != nil {
 		return err
 	}
 
 	r.Lock()
+	features := featureResp.FeatureMap()
+	r.keepLastValid(features, invalid)
+	previous, previousSegments := r.listFeatures(), r.segments
 	r.etag = resp.Header.Get("Etag")
-	r.segments = featureResp.SegmentsMap()
-	r.options.storage.Reset(featureResp.FeatureMap(), true)
+	r.segments = featureResp.Segments
+	r.options.storage.Reset(features, true)
+	r.refreshViews()
 	r.successfulFetch()
+	unknown := map[*repositoryView][]api.Operator{}
+	for v := range r.views {
+		unknown[v] = v.eval.UnknownOperators()
+	}
+	diff := diffRepository(previous, r.listFeatures(), previousSegments, r.segments)
+	etag := r.etag
 	r.Unlock()
+
+	r.stale.Store(false)
+	r.publish(FetchedEvent{ETag: etag})
+	if !diff.Empty() {
+		r.publish(UpdatedEvent{ETag: etag, Diff: diff})
+	}
+
+	for _, err := range invalid {
+		r.warnOnce(err)
+	}
+	for v, ops := range unknown {
+		for _, op := range ops {
+			v.warnOnce(fmt.Errorf("unknown constraint operator %s, constraints using it never match", op))
+		}
+	}
 	return nil
 }
 
+// keepLastValid keeps serving the previous definition of the feature toggles
+// that were rejected from a new payload, if there is one.
+func (r *repository) keepLastValid(features map[string]interface{}, invalid []error) {
+	for _, err := range invalid {
+		var featureErr *api.InvalidFeatureError
+		if !errors.As(err, &featureErr) || featureErr.Feature == "" {
+			continue
+		}
+		if _, ok := features[featureErr.Feature]; ok {
+			continue
+		}
+		if previous, ok := r.options.storage.Get(featureErr.Feature); ok {
+			features[featureErr.Feature] = previous
+		}
+	}
+}
+
+// warnOnce reports a problem with the fetched data the first time it is seen.
+// It is only called from the sync goroutine.
+func (r *repository) warnOnce(err error) {
+	if r.reported[err.Error()] {
+		return
+	}
+	r.reported[err.Error()] = true
+	r.warn(err)
+}
+
+// warnOnce reports a problem with the fetched data to the client of the view the
+// first time it is seen. It is only called from the sync goroutine.
+func (v *repositoryView) warnOnce(err error) {
+	if v.reported[err.Error()] {
+		return
+	}
+	v.reported[err.Error()] = true
+	v.repo.options.logger.Warn("unleash warning", errorAttrs(err)...)
+	v.warnings.push(err)
+}
+
 func (r *repository) statusIsOK(resp *http.Response) error {
 	s := resp.StatusCode
 	if http.StatusOK <= s && s < http.StatusMultipleChoices {
 		return nil
 	} else if s == http.StatusUnauthorized || s == http.StatusForbidden || s == http.StatusNotFound {
 		r.configurationError()
-		return fmt.Errorf("%s %s returned status code %d your SDK is most likely misconfigured, backing off to maximum (%f times our interval)", resp.Request.Method, resp.Request.URL, s, r.maxSkips)
+		return &FetchError{
+			Method:     resp.Request.Method,
+			URL:        resp.Request.URL.String(),
+			StatusCode: s,
+			detail:     fmt.Sprintf(" your SDK is most likely misconfigured, backing off to maximum (%f times our interval)", r.maxSkips),
+		}
 	} else if s == http.StatusTooManyRequests || s >= http.StatusInternalServerError {
 		r.backoff()
-		return fmt.Errorf("%s %s returned status code %d, backing off (%f times our interval)", resp.Request.Method, resp.Request.URL, s, r.errors)
+		return &FetchError{
+			Method:     resp.Request.Method,
+			URL:        resp.Request.URL.String(),
+			StatusCode: s,
+			detail:     fmt.Sprintf(", backing off (%f times our interval)", r.errors),
+		}
 	}
 
-	return fmt.Errorf("%s %s returned status code %d", resp.Request.Method, resp.Request.URL, s)
+	return &FetchError{Method: resp.Request.Method, URL: resp.Request.URL.String(), StatusCode: s}
 }
 
-func (r *repository) getToggle(key string) *api.Feature {
-	r.RLock()
-	defer r.RUnlock()
-
-	if toggle, found := r.options.storage.Get(key); found {
-		if feature, ok := toggle.(api.Feature); ok {
-			return &feature
-		}
+// refreshViews creates the evaluators of the views for the current toggles and
+// segments. It must be called with the lock held.
+func (r *repository) refreshViews() {
+	features := r.listFeatures()
+	for v := range r.views {
+		v.refresh(features, r.segments)
 	}
-	return nil
 }
 
-func (r *repository) resolveSegmentConstraints(strategy api.Strategy) ([]api.Constraint, error) {
-	segmentConstraints := []api.Constraint{}
-
-	for _, segmentId := range strategy.Segments {
-		if resolvedConstraints, ok := r.segments[segmentId]; ok {
-			segmentConstraints = append(segmentConstraints, resolvedConstraints...)
-		} else {
-			return segmentConstraints, fmt.Errorf("segment does not exist")
-		}
-	}
+// refresh creates the evaluator of the view for the given toggles and segments.
+// It must be called with the lock of the repository held.
+func (v *repositoryView) refresh(features []api.Feature, segments []api.Segment) {
+	v.eval = evaluator.New(
+		api.FeatureResponse{
+			Features: features,
+			Segments: segments,
+		},
+		v.options...,
+	)
+}
 
-	return segmentConstraints, nil
+// evaluator returns the evaluator for the current snapshot of the repository.
+func (v *repositoryView) evaluator() *evaluator.Evaluator {
+	v.repo.RLock()
+	defer v.repo.RUnlock()
+	return v.eval
 }
 
 func (r *repository) list() []api.Feature {
 	r.RLock()
 	defer r.RUnlock()
+	return r.listFeatures()
+}
 
+func (r *repository) listFeatures() []api.Feature {
 	var features []api.Feature
 	for _, feature := range r.options.storage.List() {
 		features = append(features, feature.(api.Feature))
@@ -219,10 +531,32 @@ func (r *repository) list() []api.Feature {
 	return features
 }
 
+// status describes the repository.
+func (r *repository) status() RepositoryStatus {
+	r.RLock()
+	etag, toggles, segments := r.etag, len(r.options.storage.List()), len(r.segments)
+	r.RUnlock()
+
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
+	return RepositoryStatus{
+		Source:           r.source,
+		LastFetchAttempt: r.lastAttempt,
+		LastFetchSuccess: r.lastSuccess,
+		LastStatusCode:   r.lastStatus,
+		LastError:        r.lastError,
+		ETag:             etag,
+		Toggles:          toggles,
+		Segments:         segments,
+		Backoff:          BackoffStatus{Failures: int(r.errors), Skips: int(r.skips)},
+		Stale:            r.isStale(),
+	}
+}
+
 func (r *repository) Close() error {
 	close(r.close)
 	r.cancel()
 	<-r.closed
 	r.refreshTicker.Stop()
-	return nil
+	return r.persistErr
 }
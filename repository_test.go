@@ -3,6 +3,7 @@ package unleash
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"gopkg.in/h2non/gock.v1"
 	"net/http"
 	"net/http/httptest"
@@ -186,4 +187,139 @@ func TestRepository_back_offs_are_gradually_reduced_on_success(t *testing.T) {
 	err = client.Close()
 	a.Equal(float64(3), client.repository.errors) // 4 failures, and then one success, should reduce error count to 3
 	a.Nil(err)
-}
\ No newline at end of file
+}
+type warningListener struct {
+	NoopListener
+	warnings chan error
+}
+
+func (l warningListener) OnWarning(warning error) {
+	l.warnings <- warning
+}
+
+func TestRepository_QuarantinesInvalidFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/client/features" {
+			return
+		}
+		atomic.AddInt32(&fetches, 1)
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{
+			"version": 2,
+			"features": [
+				{"name": "valid", "enabled": true, "strategies": [{"name": "default"}]},
+				{"name": "invalid", "enabled": true, "strategies": [{"name": "flexibleRollout", "parameters": {"rollout": 100, "stickiness": 42}}]},
+				{"name": "future", "enabled": true, "strategies": [{"name": "default", "constraints": [{"contextName": "userId", "operator": "FUTURE_OP", "values": ["1"]}]}]}
+			]
+		}`))
+	}))
+	defer srv.Close()
+
+	listener := warningListener{warnings: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithRefreshInterval(time.Millisecond),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	for atomic.LoadInt32(&fetches) < 5 {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.True(client.IsEnabled("valid"))
+	assert.False(client.IsEnabled("invalid"))
+	assert.False(client.IsEnabled("future"), "constraints with unknown operators never match")
+
+	assert.NoError(client.Close())
+	close(listener.warnings)
+
+	var warnings []string
+	for w := range listener.warnings {
+		warnings = append(warnings, w.Error())
+	}
+	assert.Equal([]string{
+		`feature toggle "invalid" is invalid at $.features[1].strategies[0].parameters.stickiness and was ignored: parameter stickiness of strategy flexibleRollout must be a string, got float64`,
+		"unknown constraint operator FUTURE_OP, constraints using it never match",
+	}, warnings, "each problem is reported once")
+}
+
+func TestRepository_ReportsFetchError(t *testing.T) {
+	a := assert.New(t)
+	defer gock.Off()
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(503)
+
+	listener := errorCollector{errors: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithDisableMetrics(true),
+		WithInstanceId(mockInstanceId),
+		WithListener(listener),
+	)
+	a.Nil(err)
+
+	var fetchErr *FetchError
+	a.True(errors.As(<-listener.errors, &fetchErr))
+	a.Equal(503, fetchErr.StatusCode)
+	a.Equal("GET", fetchErr.Method)
+	a.Equal(mockerServer+"/client/features", fetchErr.URL)
+
+	a.Nil(client.Close())
+}
+
+type updateListener struct {
+	NoopListener
+	updates chan RepositoryDiff
+}
+
+func (l updateListener) OnUpdate(diff RepositoryDiff) {
+	l.updates <- diff
+}
+
+func TestRepository_ReportsChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/client/features" {
+			return
+		}
+		features := []api.Feature{{Name: "feature", Enabled: false}}
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			features = []api.Feature{{Name: "feature", Enabled: true}}
+		}
+		writeJSON(rw, api.FeatureResponse{Features: features})
+	}))
+	defer srv.Close()
+
+	listener := updateListener{updates: make(chan RepositoryDiff, 10)}
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithRefreshInterval(time.Millisecond),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+
+	assert.Equal(RepositoryDiff{Added: []string{"feature"}}, <-listener.updates)
+	assert.Equal(RepositoryDiff{Enabled: []string{"feature"}}, <-listener.updates)
+
+	for atomic.LoadInt32(&fetches) < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.NoError(client.Close())
+	assert.Len(listener.updates, 0, "fetches without changes are not reported")
+}
@@ -0,0 +1,104 @@
+package unleash
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Repository fetches the feature toggles from the Unleash server and holds them,
+// so that several clients can share a single polling loop and backup file. Each
+// client sharing the repository keeps its own static context, strategies,
+// listener and metrics.
+//
+// A repository is closed once it was closed by its creator and by every client
+// using it.
+type Repository struct {
+	repo    *repository
+	options configOption
+	once    sync.Once
+}
+
+// NewRepository creates a repository configured with the options of a client
+// that relate to fetching and storing the feature toggles: WithUrl, WithAppName,
+// WithInstanceId, WithProjectName, WithProjects, WithNamePrefix, WithTags,
+// WithRefreshInterval, WithBackupPath,
+// WithStorage, WithHttpClient, WithCustomHeaders, WithClock, WithMaxDataAge and
+// WithLogger. The other options are ignored. It starts fetching the feature
+// toggles right away.
+//
+// The errors and warnings of the repository are reported to the clients using
+// it at the time they occur, and logged to its logger.
+func NewRepository(options ...ConfigOption) (*Repository, error) {
+	o := defaultConfig()
+	for _, opt := range options {
+		opt(&o)
+	}
+	o.logger = loggerOrDiscard(o.logger)
+
+	if o.url == "" {
+		return nil, fmt.Errorf("unleash server URL missing")
+	}
+	if strings.HasSuffix(o.url, deprecatedSuffix) {
+		o.logger.Warn("unleash server URL should no longer link directly to /features", "url", o.url)
+		o.url = strings.TrimSuffix(o.url, deprecatedSuffix)
+	}
+	if !strings.HasSuffix(o.url, "/") {
+		o.url += "/"
+	}
+	parsedUrl, err := url.Parse(o.url)
+	if err != nil {
+		return nil, err
+	}
+	if o.appName == "" {
+		return nil, fmt.Errorf("unleash client appName missing")
+	}
+	if err := validateTags(o.tags); err != nil {
+		return nil, err
+	}
+	if o.instanceId == "" {
+		o.instanceId = generateInstanceId()
+	}
+	if bs, ok := o.storage.(*BootstrapStorage); ok && bs.Logger == nil {
+		bs.Logger = o.logger
+	}
+
+	r := &Repository{repo: newRepository(o.repositoryOptions(*parsedUrl)), options: o}
+	r.repo.start()
+	return r, nil
+}
+
+// inherit sets the options of a client sharing the repository that were left
+// unset to those of the repository, so that the client sends its metrics to the
+// same server with the same credentials.
+func (r *Repository) inherit(o *configOption) {
+	if o.url == "" {
+		o.url = r.options.url
+	}
+	if o.appName == "" {
+		o.appName = r.options.appName
+	}
+	if o.httpClient == nil {
+		o.httpClient = r.options.httpClient
+	}
+	if o.customHeaders == nil {
+		o.customHeaders = r.options.customHeaders
+	}
+}
+
+// Status describes the feature toggles held by the repository.
+func (r *Repository) Status() RepositoryStatus {
+	return r.repo.status()
+}
+
+// Close releases the reference of the creator of the repository. The repository
+// stops fetching the feature toggles and persists them once every client using
+// it is closed too. It is safe to call Close more than once.
+func (r *Repository) Close() error {
+	var err error
+	r.once.Do(func() {
+		err = r.repo.release()
+	})
+	return err
+}
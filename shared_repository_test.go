@@ -0,0 +1,94 @@
+package unleash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+)
+
+type alwaysOnStrategy struct{}
+
+func (alwaysOnStrategy) Name() string {
+	return "alwaysOn"
+}
+
+func (alwaysOnStrategy) IsEnabled(map[string]interface{}, *context.Context) bool {
+	return true
+}
+
+func TestRepository_Shared(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/client/features" {
+			return
+		}
+		assert.Equal("secret", req.Header.Get("Authorization"))
+		atomic.AddInt32(&fetches, 1)
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{
+			{Name: "prod-only", Enabled: true, Strategies: []api.Strategy{{
+				Name:        "default",
+				Constraints: []api.Constraint{{ContextName: "environment", Operator: api.OperatorIn, Values: []string{"prod"}}},
+			}}},
+			{Name: "custom", Enabled: true, Strategies: []api.Strategy{{Name: "alwaysOn"}}},
+		}})
+	}))
+	defer srv.Close()
+
+	repo, err := NewRepository(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithCustomHeaders(http.Header{"Authorization": {"secret"}}),
+		WithRefreshInterval(time.Hour),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+
+	prod, err := NewClient(
+		WithSharedRepository(repo),
+		WithEnvironment("prod"),
+		WithStrategies(alwaysOnStrategy{}),
+		WithDisableMetrics(true),
+	)
+	assert.NoError(err)
+	prod.WaitForReady()
+
+	dev, err := NewClient(
+		WithSharedRepository(repo),
+		WithEnvironment("dev"),
+		WithDisableMetrics(true),
+	)
+	assert.NoError(err)
+	dev.WaitForReady()
+
+	assert.True(prod.IsEnabled("prod-only"))
+	assert.True(prod.IsEnabled("custom"))
+	assert.False(dev.IsEnabled("prod-only"), "each client keeps its own static context")
+	assert.False(dev.IsEnabled("custom"), "each client keeps its own strategies")
+	assert.Equal(int32(1), atomic.LoadInt32(&fetches))
+	assert.Equal(2, repo.Status().Toggles)
+
+	assert.NoError(repo.Close())
+	assert.NoError(repo.Close())
+	assert.NoError(prod.Close())
+	assert.True(dev.IsEnabled("prod-only", WithContext(context.Context{Environment: "prod"})))
+	select {
+	case <-repo.repo.closed:
+		t.Fatal("the repository is closed while a client uses it")
+	default:
+	}
+
+	assert.NoError(dev.Close())
+	<-repo.repo.closed
+
+	_, err = NewClient(WithSharedRepository(repo))
+	assert.EqualError(err, "the shared repository is closed")
+}
@@ -0,0 +1,100 @@
+package unleash
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientClosed is reported when a feature toggle is evaluated after the client
+// was closed. The evaluation returns the fallback value, as for a feature toggle
+// that does not exist. It is also returned by Client.Err once the client is
+// closed.
+var ErrClientClosed = errors.New("unleash client is closed")
+
+// Err returns ErrClientClosed once Close or Shutdown was called, and nil before.
+func (uc *Client) Err() error {
+	if uc.isClosed.Load() {
+		return ErrClientClosed
+	}
+	return nil
+}
+
+// Close stops the client from syncing data from the server. The metrics not sent
+// yet are dropped; use Shutdown to send them. It is safe to call Close more than
+// once, and after Shutdown: only the first call closes the client.
+func (uc *Client) Close() error {
+	return uc.shutdown(context.Background(), false)
+}
+
+// Shutdown closes the client like Close, after sending the pending metrics. The
+// storage is persisted before Shutdown returns, unless the repository is shared
+// with other clients. If the context is done first, the requests in flight are
+// cancelled and the context error is returned while the client finishes closing
+// in the background.
+//
+// It is safe to call Shutdown more than once, and after Close: only the first
+// call closes the client.
+func (uc *Client) Shutdown(ctx context.Context) error {
+	return uc.shutdown(ctx, true)
+}
+
+// shutdown closes the client once, sending the pending metrics if flush is true.
+func (uc *Client) shutdown(ctx context.Context, flush bool) (err error) {
+	uc.closeOnce.Do(func() {
+		err = uc.closeClient(ctx, flush)
+	})
+	return err
+}
+
+func (uc *Client) closeClient(ctx context.Context, flush bool) error {
+	uc.isClosed.Store(true)
+
+	var metricsErr error
+	if flush {
+		metricsErr = uc.metrics.Shutdown(ctx)
+	} else {
+		uc.metrics.Close()
+	}
+
+	var persistErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		persistErr = uc.repository.detach(uc.view)
+		// Wait for sync to deliver the queued events and exit.
+		close(uc.close)
+		<-uc.closed
+		uc.providersWg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return errors.Join(metricsErr, persistErr)
+}
+
+// reportClosed reports an evaluation made after the client was closed. The error
+// is queued while sync is still delivering the queued events, so that handlers
+// keep being called one at a time; once sync has exited it is only logged.
+func (uc *Client) reportClosed(opts featureOption) {
+	opts.explain("the client is closed")
+	if !uc.errorLimiter.allow(ErrClientClosed) {
+		return
+	}
+	uc.options.logger.Error("unleash error", errorAttrs(ErrClientClosed)...)
+	select {
+	case <-uc.closed:
+	default:
+		uc.errors.tryPush(ErrClientClosed)
+	}
+}
+
+// Shutdown will send the pending metrics of the default client and close it.
+func Shutdown(ctx context.Context) error {
+	if defaultClient == nil {
+		return nil
+	}
+	return defaultClient.Shutdown(ctx)
+}
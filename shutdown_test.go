@@ -0,0 +1,180 @@
+package unleash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// shutdownServer serves a single enabled feature toggle and hands the metrics
+// it receives to the given function.
+func shutdownServer(onMetrics func(rw http.ResponseWriter, req *http.Request)) *httptest.Server {
+	features := api.FeatureResponse{Features: []api.Feature{
+		{Name: "feature", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+	}}
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/client/features":
+			writeJSON(rw, features)
+		case "/client/metrics":
+			onMetrics(rw, req)
+		default:
+			rw.WriteHeader(http.StatusOK)
+		}
+	}))
+}
+
+func TestClient_CloseIsIdempotent(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := shutdownServer(func(rw http.ResponseWriter, req *http.Request) {})
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.NoError(client.Close())
+	assert.NoError(client.Close())
+	assert.NoError(client.Shutdown(context.Background()))
+}
+
+func TestClient_ShutdownFlushesMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	received := make(chan MetricsData, 1)
+	srv := shutdownServer(func(rw http.ResponseWriter, req *http.Request) {
+		var md MetricsData
+		json.NewDecoder(req.Body).Decode(&md)
+		received <- md
+		rw.WriteHeader(http.StatusOK)
+	})
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithMetricsInterval(time.Hour),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	client.IsEnabled("feature")
+	client.IsEnabled("feature")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(client.Shutdown(ctx))
+
+	select {
+	case md := <-received:
+		assert.Equal(int32(2), md.Bucket.Toggles["feature"].Yes)
+	default:
+		t.Fatal("the pending metrics were not sent")
+	}
+}
+
+func TestClient_ShutdownRespectsDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := shutdownServer(func(rw http.ResponseWriter, req *http.Request) {
+		// The connection is only watched once the body is read.
+		io.Copy(io.Discard, req.Body)
+		<-req.Context().Done()
+	})
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithMetricsInterval(time.Hour),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+	client.IsEnabled("feature")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(context.DeadlineExceeded, client.Shutdown(ctx))
+	assert.NoError(client.Close())
+}
+
+func TestClient_EvaluateAfterClose(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := shutdownServer(func(rw http.ResponseWriter, req *http.Request) {})
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithBackupPath(t.TempDir()),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+	assert.True(client.IsEnabled("feature"))
+	assert.NoError(client.Close())
+
+	assert.False(client.IsEnabled("feature"))
+	assert.True(client.IsEnabled("feature", WithFallback(true)))
+	assert.Equal(api.GetDefaultVariant(), client.GetVariant("feature"))
+
+	details := client.Explain("feature")
+	assert.False(details.Found)
+	assert.Contains(details.Reasons, "the client is closed")
+
+	records := logRecords(t, &buf)
+	if assert.Len(records, 1) {
+		assert.Equal("ERROR", records[0]["level"])
+		assert.Equal(ErrClientClosed.Error(), records[0]["error"])
+	}
+}
+
+func TestClient_EvaluateAfterCloseDefaultConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := shutdownServer(func(rw http.ResponseWriter, req *http.Request) {})
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+	assert.NoError(client.Err())
+
+	var reported []error
+	client.Subscribe(func(e Event) {
+		reported = append(reported, e.(ErrorEvent).Err)
+	}, EventError)
+	assert.NoError(client.Close())
+	assert.Equal(ErrClientClosed, client.Err())
+
+	assert.False(client.IsEnabled("feature"))
+	assert.False(client.IsEnabled("feature"))
+	assert.Empty(reported, "handlers are not called once the client is closed")
+}
@@ -82,7 +82,7 @@ func (vtc VariantTestCase) GetDescription() string {
 }
 
 func (vtc VariantTestCase) RunWithClient(client *Client) func(*testing.T) {
-	client.staticContext = &vtc.Context
+	client.staticContext = newContextProviders(vtc.Context, nil)
 	return func(t *testing.T) {
 		client.WaitForReady()
 		var wg sync.WaitGroup
@@ -141,7 +141,7 @@ func (td TestDefinition) Run(t *testing.T) {
 		listener.On("OnReady").Return()
 		listener.On("OnRegistered", mock.AnythingOfType("ClientData")).Return()
 		listener.On("OnCount", mock.AnythingOfType("string"), mock.AnythingOfType("bool")).Return()
-		listener.On("OnError", mock.AnythingOfType("*errors.errorString")).Return()
+		listener.On("OnError", mock.Anything).Return()
 
 		client, err := td.Mock(listener)
 		assert.NoError(t, err)
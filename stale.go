@@ -0,0 +1,53 @@
+package unleash
+
+import (
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/evaluator"
+)
+
+// StalePolicy decides how feature toggles are evaluated while the data of the
+// repository is older than the maximum age set with WithMaxDataAge.
+type StalePolicy int
+
+const (
+	// StaleKeepServing keeps evaluating the stale feature toggles. It is the
+	// default policy.
+	StaleKeepServing StalePolicy = iota
+
+	// StaleUseFallbacks evaluates the stale feature toggles as if they were not
+	// found, so that the fallbacks of the call or the declared defaults are used.
+	StaleUseFallbacks
+
+	// StaleFailClosed evaluates the stale feature toggles as disabled.
+	StaleFailClosed
+)
+
+// stalePolicy returns the policy applying to the feature toggle while the data is
+// stale.
+func (uc *Client) stalePolicy(feature *api.Feature) StalePolicy {
+	types := uc.options.staleTypes
+	if len(types) > 0 && !contains(types, feature.Type) {
+		return StaleKeepServing
+	}
+	return uc.options.stalePolicy
+}
+
+// applyStalePolicy changes the result of an evaluation of the repository when its
+// data is stale. It reports whether the feature toggle is treated as not found
+// although it exists.
+func (uc *Client) applyStalePolicy(opts featureOption, result *evaluator.Result) bool {
+	if result.Feature == nil || !uc.repository.isStale() {
+		return false
+	}
+	switch uc.stalePolicy(result.Feature) {
+	case StaleUseFallbacks:
+		opts.explain("feature toggles are stale, using the fallbacks")
+		*result = evaluator.Result{}
+		return true
+	case StaleFailClosed:
+		opts.explain("feature toggles are stale, failing closed")
+		result.Enabled = false
+		result.Variant = nil
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package unleash
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_StalePolicy(t *testing.T) {
+	features := api.FeatureResponse{Features: []api.Feature{
+		{Name: "release", Type: "release", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+		{Name: "kill", Type: "kill-switch", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+	}}
+
+	testCases := []struct {
+		name         string
+		policy       ConfigOption
+		release      bool
+		kill         bool
+		killFallback bool
+	}{
+		{"keep serving", WithStalePolicy(StaleKeepServing), true, true, true},
+		{"fail closed by type", WithStalePolicy(StaleFailClosed, "kill-switch"), true, false, false},
+		{"use fallbacks", WithStalePolicy(StaleUseFallbacks), false, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var failing int32
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if atomic.LoadInt32(&failing) == 1 {
+					rw.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				writeJSON(rw, features)
+			}))
+			defer srv.Close()
+
+			fake := clock.NewFake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+			listener := warningListener{warnings: make(chan error, 10)}
+			client, err := NewClient(
+				WithUrl(srv.URL),
+				WithAppName(mockAppName),
+				WithInstanceId(mockInstanceId),
+				WithDisableMetrics(true),
+				WithListener(listener),
+				WithClock(fake),
+				WithRefreshInterval(time.Minute),
+				WithBackupPath(t.TempDir()),
+				WithMaxDataAge(time.Hour),
+				tc.policy,
+			)
+			assert.NoError(err)
+			client.WaitForReady()
+
+			atomic.StoreInt32(&failing, 1)
+			fake.Advance(time.Hour + time.Minute)
+
+			var staleErr *StaleDataError
+			assert.True(errors.As(<-listener.warnings, &staleErr))
+			assert.Equal(time.Hour, staleErr.MaxAge)
+			assert.Equal(time.Hour+time.Minute, staleErr.Age)
+			assert.True(client.Status().Repository.Stale)
+
+			assert.Equal(tc.release, client.IsEnabled("release"))
+			assert.Equal(tc.kill, client.IsEnabled("kill"))
+			assert.Equal(tc.killFallback, client.IsEnabled("kill", WithFallback(true)))
+
+			assert.NoError(client.Close())
+		})
+	}
+}
+
+func TestClient_StaleDataRecovers(t *testing.T) {
+	assert := assert.New(t)
+
+	var failing int32
+	fetched := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		defer func() { fetched <- struct{}{} }()
+		if atomic.LoadInt32(&failing) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{
+			{Name: "kill", Type: "kill-switch", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+		}})
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	listener := warningListener{warnings: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithClock(fake),
+		WithRefreshInterval(time.Minute),
+		WithBackupPath(t.TempDir()),
+		WithMaxDataAge(time.Hour),
+		WithStalePolicy(StaleFailClosed),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+	<-fetched
+
+	atomic.StoreInt32(&failing, 1)
+	fake.Advance(2 * time.Hour)
+	<-listener.warnings
+	assert.False(client.IsEnabled("kill"))
+
+	// The failed fetch backed off for one interval.
+	atomic.StoreInt32(&failing, 0)
+	fake.Advance(time.Minute)
+	fake.Advance(time.Minute)
+	<-fetched
+	<-fetched
+	for client.Status().Repository.Stale {
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(client.IsEnabled("kill"))
+
+	assert.NoError(client.Close())
+}
+
+func TestClient_StaleBackup(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		saved time.Time
+		stale bool
+	}{
+		{"old backup", now.Add(-3 * time.Hour), true},
+		{"recent backup", now.Add(-10 * time.Minute), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer srv.Close()
+
+			backupPath := t.TempDir()
+			backup := filepath.Join(backupPath, fmt.Sprintf("unleash-repo-schema-v1-%s.json", mockAppName))
+			data, _ := json.Marshal(map[string]api.Feature{
+				"kill": {Name: "kill", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+			})
+			assert.NoError(os.WriteFile(backup, data, 0644))
+			assert.NoError(os.Chtimes(backup, tc.saved, tc.saved))
+
+			listener := warningListener{warnings: make(chan error, 10)}
+			client, err := NewClient(
+				WithUrl(srv.URL),
+				WithAppName(mockAppName),
+				WithInstanceId(mockInstanceId),
+				WithDisableMetrics(true),
+				WithListener(listener),
+				WithClock(clock.NewFake(now)),
+				WithRefreshInterval(time.Minute),
+				WithBackupPath(backupPath),
+				WithMaxDataAge(time.Hour),
+				WithStalePolicy(StaleFailClosed),
+			)
+			assert.NoError(err)
+
+			for client.Status().Repository.LastError == "" {
+				time.Sleep(time.Millisecond)
+			}
+			if tc.stale {
+				var staleErr *StaleDataError
+				assert.True(errors.As(<-listener.warnings, &staleErr))
+				assert.Equal(3*time.Hour, staleErr.Age)
+			}
+			assert.Equal(tc.stale, client.Status().Repository.Stale)
+			assert.Equal(!tc.stale, client.IsEnabled("kill"))
+
+			assert.NoError(client.Close())
+		})
+	}
+}
+
+func TestClient_StaleBootstrap(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	bootstrap, _ := json.Marshal(api.FeatureResponse{Features: []api.Feature{
+		{Name: "kill", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+	}})
+	listener := warningListener{warnings: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithClock(clock.NewFake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))),
+		WithBackupPath(t.TempDir()),
+		WithStorage(&BootstrapStorage{Reader: bytes.NewReader(bootstrap)}),
+		WithMaxDataAge(time.Hour),
+		WithStalePolicy(StaleFailClosed),
+	)
+	assert.NoError(err)
+
+	var staleErr *StaleDataError
+	assert.True(errors.As(<-listener.warnings, &staleErr))
+	assert.Zero(staleErr.Age, "the age of bootstrap data is unknown")
+	assert.True(client.Status().Repository.Stale)
+	assert.False(client.IsEnabled("kill"))
+
+	assert.NoError(client.Close())
+}
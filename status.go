@@ -0,0 +1,162 @@
+package unleash
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// DataSource tells where the feature toggles served by the client come from.
+type DataSource string
+
+const (
+	// DataSourceNone means that no feature toggles were loaded yet.
+	DataSourceNone DataSource = "none"
+
+	// DataSourceBootstrap means that the feature toggles come from the reader of
+	// a BootstrapStorage.
+	DataSourceBootstrap DataSource = "bootstrap"
+
+	// DataSourceBackup means that the feature toggles come from the backup
+	// persisted by a previous run.
+	DataSourceBackup DataSource = "backup"
+
+	// DataSourceServer means that the feature toggles were fetched from the
+	// Unleash server.
+	DataSourceServer DataSource = "server"
+)
+
+// BackoffStatus describes how a periodic request of the client backs off after
+// failures.
+type BackoffStatus struct {
+	// Failures is the current failure count, which is decreased by each success.
+	Failures int `json:"failures"`
+
+	// Skips is the number of intervals left before the next request.
+	Skips int `json:"skips"`
+}
+
+// RepositoryStatus describes the feature toggles held by the client and how they
+// are kept up to date.
+type RepositoryStatus struct {
+	// Source tells where the feature toggles come from.
+	Source DataSource `json:"source"`
+
+	// LastFetchAttempt is the time of the last request for the feature toggles.
+	LastFetchAttempt time.Time `json:"lastFetchAttempt"`
+
+	// LastFetchSuccess is the time of the last successful request for the
+	// feature toggles, including those answered with 304 Not Modified.
+	LastFetchSuccess time.Time `json:"lastFetchSuccess"`
+
+	// LastStatusCode is the status code of the last response of the server, or
+	// zero if there was none.
+	LastStatusCode int `json:"lastStatusCode"`
+
+	// LastError is the error of the last request, or empty if it succeeded.
+	LastError string `json:"lastError,omitempty"`
+
+	// ETag is the version of the feature toggles reported by the server.
+	ETag string `json:"etag"`
+
+	// Toggles is the number of feature toggles.
+	Toggles int `json:"toggles"`
+
+	// Segments is the number of segments.
+	Segments int `json:"segments"`
+
+	// Backoff describes the backoff of the requests for the feature toggles.
+	Backoff BackoffStatus `json:"backoff"`
+
+	// Stale indicates whether the feature toggles are older than the maximum
+	// age set with WithMaxDataAge.
+	Stale bool `json:"stale"`
+}
+
+// MetricsStatus describes the reporting of metrics to the Unleash server.
+type MetricsStatus struct {
+	// Disabled indicates whether metrics are disabled.
+	Disabled bool `json:"disabled"`
+
+	// Registered indicates whether the client has registered with the server.
+	Registered bool `json:"registered"`
+
+	// Backoff describes the backoff of the requests sending the metrics.
+	Backoff BackoffStatus `json:"backoff"`
+}
+
+// Status describes the state of a client, for instance to tell whether it is
+// serving stale feature toggles.
+type Status struct {
+	// Ready indicates whether the feature toggles have been fetched from the
+	// server at least once.
+	Ready bool `json:"ready"`
+
+	// Repository describes the feature toggles.
+	Repository RepositoryStatus `json:"repository"`
+
+	// Metrics describes the reporting of metrics.
+	Metrics MetricsStatus `json:"metrics"`
+}
+
+// Serving reports whether the client has feature toggles to serve, whatever
+// their source.
+func (s Status) Serving() bool {
+	return s.Repository.Source != DataSourceNone
+}
+
+// Status returns the current state of the client.
+//
+// It is safe to call this method from multiple goroutines concurrently.
+func (uc *Client) Status() Status {
+	ready := false
+	select {
+	case <-uc.onReady:
+		ready = true
+	default:
+	}
+	return Status{
+		Ready:      ready,
+		Repository: uc.repository.status(),
+		Metrics:    uc.metrics.status(),
+	}
+}
+
+// StatusHandler returns an http.Handler serving the status of the client as JSON,
+// suitable for readiness probes. It responds with 200 OK when the client has
+// feature toggles to serve, whether from the server, a backup or a bootstrap,
+// and with 503 Service Unavailable otherwise. Feature toggles older than the
+// maximum age set with WithMaxDataAge are not considered fit to serve, whatever
+// the stale policy.
+//
+// Do not use it for liveness probes: an outage of the Unleash server would fail
+// the probes of every instance and get them all restarted. Use LivenessHandler
+// instead.
+func (uc *Client) StatusHandler() http.Handler {
+	return uc.statusHandler(func(status Status) bool {
+		return status.Serving() && !status.Repository.Stale
+	})
+}
+
+// LivenessHandler returns an http.Handler serving the status of the client as
+// JSON, suitable for liveness probes. It responds with 200 OK until the client is
+// closed, whatever the state of the feature toggles and of the fetches, and with
+// 503 Service Unavailable afterwards.
+func (uc *Client) LivenessHandler() http.Handler {
+	return uc.statusHandler(func(Status) bool {
+		return uc.Err() == nil
+	})
+}
+
+// statusHandler serves the status, with 503 Service Unavailable when healthy
+// reports false.
+func (uc *Client) statusHandler(healthy func(Status) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := uc.Status()
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy(status) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
@@ -0,0 +1,194 @@
+package unleash
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_Status(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Reply(200).
+		SetHeader("Etag", `"v1"`).
+		JSON(api.FeatureResponse{
+			Features: []api.Feature{{Name: "a"}, {Name: "b"}},
+			Segments: []api.Segment{{Id: 1}},
+		})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(&NoopListener{}),
+		WithClock(clock.NewFake(now)),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.Equal(Status{
+		Ready: true,
+		Repository: RepositoryStatus{
+			Source:           DataSourceServer,
+			LastFetchAttempt: now,
+			LastFetchSuccess: now,
+			LastStatusCode:   200,
+			ETag:             `"v1"`,
+			Toggles:          2,
+			Segments:         1,
+		},
+		Metrics: MetricsStatus{Disabled: true},
+	}, client.Status())
+
+	assert.NoError(client.Close())
+}
+
+func TestClient_StatusHandler(t *testing.T) {
+	assert := assert.New(t)
+	defer gock.OffAll()
+
+	gock.New(mockerServer).
+		Get("/client/features").
+		Persist().
+		Reply(503)
+
+	serve := func(client *Client) (int, Status) {
+		rec := httptest.NewRecorder()
+		client.StatusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+		var status Status
+		assert.NoError(json.NewDecoder(rec.Body).Decode(&status))
+		assert.Equal("application/json", rec.Header().Get("Content-Type"))
+		return rec.Code, status
+	}
+
+	errs := errorCollector{errors: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(errs),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	<-errs.errors
+
+	code, status := serve(client)
+	assert.Equal(http.StatusServiceUnavailable, code)
+	assert.False(status.Ready)
+	assert.Equal(DataSourceNone, status.Repository.Source)
+	assert.Equal(503, status.Repository.LastStatusCode)
+	assert.Contains(status.Repository.LastError, "returned status code 503")
+	assert.Equal(BackoffStatus{Failures: 1, Skips: 1}, status.Repository.Backoff)
+	assert.NoError(client.Close())
+
+	client, err = NewClient(
+		WithUrl(mockerServer),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(errs),
+		WithBackupPath(t.TempDir()),
+		WithStorage(&BootstrapStorage{Reader: strings.NewReader(`{"version": 2, "features": [{"name": "a"}]}`)}),
+	)
+	assert.NoError(err)
+	<-errs.errors
+
+	code, status = serve(client)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(DataSourceBootstrap, status.Repository.Source)
+	assert.Equal(1, status.Repository.Toggles)
+	assert.NoError(client.Close())
+}
+
+func TestClient_StatusFailedRegistration(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/client/register" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(rw, api.FeatureResponse{})
+	}))
+	defer srv.Close()
+
+	listener := warningListener{warnings: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithMetricsInterval(time.Hour),
+		WithListener(listener),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	assert.Contains((<-listener.warnings).Error(), "return 401")
+	assert.False(client.Status().Metrics.Registered)
+	assert.Len(client.registered, 0, "no registration is delivered")
+	assert.NoError(client.Close())
+}
+
+func TestClient_StatusHandlerStale(t *testing.T) {
+	assert := assert.New(t)
+
+	var failing int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{{Name: "a"}}})
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	listener := warningListener{warnings: make(chan error, 10)}
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithListener(listener),
+		WithClock(fake),
+		WithRefreshInterval(time.Minute),
+		WithBackupPath(t.TempDir()),
+		WithMaxDataAge(time.Hour),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	serve := func(handler http.Handler) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+		return rec.Code
+	}
+	assert.Equal(http.StatusOK, serve(client.StatusHandler()))
+	assert.Equal(http.StatusOK, serve(client.LivenessHandler()))
+
+	atomic.StoreInt32(&failing, 1)
+	fake.Advance(time.Hour + time.Minute)
+	<-listener.warnings
+	assert.Equal(http.StatusServiceUnavailable, serve(client.StatusHandler()))
+	assert.Equal(http.StatusOK, serve(client.LivenessHandler()), "stale data does not fail the liveness probe")
+
+	assert.NoError(client.Close())
+	assert.Equal(http.StatusServiceUnavailable, serve(client.LivenessHandler()))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/Unleash/unleash-client-go/v4/api"
 )
@@ -36,11 +37,20 @@ type Storage interface {
 	List() []interface{}
 }
 
+// datedStorage is implemented by the storages knowing when the data they load was
+// saved, which is the age of the data until a fetch succeeds.
+type datedStorage interface {
+	savedAt() time.Time
+}
+
 // DefaultStorage is a default Storage implementation.
 type DefaultStorage struct {
 	appName string
 	path    string
 	data    map[string]interface{}
+
+	// modTime is the modification time of the backup last loaded, or zero.
+	modTime time.Time
 }
 
 func (ds *DefaultStorage) Init(backupPath, appName string) {
@@ -71,6 +81,9 @@ func (ds *DefaultStorage) Load() error {
 		for key, value := range featuresFromFile {
 			ds.data[key] = value
 		}
+		if info, err := file.Stat(); err == nil {
+			ds.modTime = info.ModTime()
+		}
 	}
 	return nil
 }
@@ -93,6 +106,12 @@ func (ds DefaultStorage) Get(key string) (interface{}, bool) {
 	return val, ok
 }
 
+// savedAt returns the time the loaded backup was written, or zero if no backup
+// was loaded.
+func (ds *DefaultStorage) savedAt() time.Time {
+	return ds.modTime
+}
+
 func (ds *DefaultStorage) List() []interface{} {
 	var features []interface{}
 	for _, val := range ds.data {
@@ -35,6 +35,37 @@ type RepositoryListener interface {
 	OnReady()
 }
 
+// UpdateListener defines an interface that can be implemented in order to be
+// notified of the changes to the feature toggles.
+type UpdateListener interface {
+	// OnUpdate is called after a fetch changed the feature toggles or segments of
+	// the repository, with a description of the changes.
+	OnUpdate(RepositoryDiff)
+}
+
+// FeatureClient is the part of Client used by applications to evaluate feature
+// toggles. Depending on it rather than on *Client allows replacing the client in
+// tests, for instance with a FakeClient.
+type FeatureClient interface {
+	// IsEnabled queries whether the specified feature is enabled or not.
+	IsEnabled(feature string, options ...FeatureOption) bool
+
+	// GetVariant queries a variant as the specified feature is enabled.
+	GetVariant(feature string, options ...VariantOption) *api.Variant
+
+	// ListFeatures returns all available features toggles, or only those
+	// matching every given filter.
+	ListFeatures(filters ...FeatureFilter) []api.Feature
+
+	// WaitForReady blocks until the feature toggles have been loaded.
+	WaitForReady()
+
+	// Close stops the client.
+	Close() error
+}
+
+var _ FeatureClient = (*Client)(nil)
+
 // IsEnabled queries the default client whether or not the specified feature is enabled or not.
 func IsEnabled(feature string, options ...FeatureOption) bool {
 	if defaultClient == nil {
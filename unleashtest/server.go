@@ -0,0 +1,390 @@
+// Package unleashtest provides an in-process fake Unleash server for testing
+// applications that use the client.
+package unleashtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4"
+	"github.com/Unleash/unleash-client-go/v4/api"
+)
+
+// Endpoint identifies one of the endpoints of the fake server.
+type Endpoint string
+
+const (
+	// EndpointFeatures is the endpoint serving the feature toggles.
+	EndpointFeatures Endpoint = "/client/features"
+
+	// EndpointRegister is the endpoint clients register with.
+	EndpointRegister Endpoint = "/client/register"
+
+	// EndpointMetrics is the endpoint receiving the metrics.
+	EndpointMetrics Endpoint = "/client/metrics"
+)
+
+// assertTimeout is how long the assertions wait for the expected requests.
+const assertTimeout = time.Second
+
+// Server is a fake Unleash server with a programmable set of feature toggles. It
+// applies the project, name prefix and tag filters of the clients, and records
+// the registrations and metrics they send. All methods are safe to call from
+// multiple goroutines concurrently.
+type Server struct {
+	// URL is the base URL of the server, to be passed to unleash.WithUrl.
+	URL string
+
+	srv           *httptest.Server
+	mu            sync.Mutex
+	features      map[string]api.Feature
+	segments      []api.Segment
+	version       int
+	failures      map[Endpoint]int
+	latency       time.Duration
+	registrations []unleash.ClientData
+	metrics       []unleash.MetricsData
+	requests      map[Endpoint]int
+}
+
+// NewServer starts a fake server without feature toggles. It should be closed
+// when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		features: map[string]api.Feature{},
+		failures: map[Endpoint]int{},
+		requests: map[Endpoint]int{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// SetFeature adds the feature toggle, replacing any toggle with the same name.
+func (s *Server) SetFeature(feature api.Feature) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.features[feature.Name] = feature
+	s.version++
+}
+
+// RemoveFeature removes the named feature toggle.
+func (s *Server) RemoveFeature(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.features, name)
+	s.version++
+}
+
+// Enable enables the named feature toggle. A toggle that doesn't exist yet is
+// created with the default strategy.
+func (s *Server) Enable(name string) {
+	s.update(name, func(f *api.Feature) {
+		f.Enabled = true
+	})
+}
+
+// Disable disables the named feature toggle.
+func (s *Server) Disable(name string) {
+	s.update(name, func(f *api.Feature) {
+		f.Enabled = false
+	})
+}
+
+// SetVariants replaces the variants of the named feature toggle. A toggle that
+// doesn't exist yet is created enabled with the default strategy.
+func (s *Server) SetVariants(name string, variants ...api.VariantInternal) {
+	s.update(name, func(f *api.Feature) {
+		f.Variants = variants
+	})
+}
+
+// SetSegments replaces the segments served with the feature toggles.
+func (s *Server) SetSegments(segments ...api.Segment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segments = segments
+	s.version++
+}
+
+func (s *Server) update(name string, fn func(*api.Feature)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.features[name]
+	if !ok {
+		f = api.Feature{
+			Name:       name,
+			Type:       "release",
+			Enabled:    true,
+			Strategies: []api.Strategy{{Id: 1, Name: "default"}},
+		}
+	}
+	fn(&f)
+	s.features[name] = f
+	s.version++
+}
+
+// Fail makes the endpoint respond with the status code until Recover is called.
+func (s *Server) Fail(endpoint Endpoint, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = statusCode
+}
+
+// Recover makes the endpoint respond normally again.
+func (s *Server) Recover(endpoint Endpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, endpoint)
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Requests returns the number of requests received by the endpoint.
+func (s *Server) Requests(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// Registrations returns the registrations received so far.
+func (s *Server) Registrations() []unleash.ClientData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]unleash.ClientData(nil), s.registrations...)
+}
+
+// Metrics returns the metrics payloads received so far.
+func (s *Server) Metrics() []unleash.MetricsData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]unleash.MetricsData(nil), s.metrics...)
+}
+
+// Counts returns how many times the feature toggle was reported enabled and
+// disabled across all the metrics received so far.
+func (s *Server) Counts(feature string) (yes, no int32) {
+	for _, md := range s.Metrics() {
+		tc := md.Bucket.Toggles[feature]
+		yes += tc.Yes
+		no += tc.No
+	}
+	return yes, no
+}
+
+// AssertRegistered checks that a client with the application name registered,
+// waiting up to a second for the registration to arrive.
+func (s *Server) AssertRegistered(t testing.TB, appName string) bool {
+	t.Helper()
+	ok := eventually(func() bool {
+		for _, cd := range s.Registrations() {
+			if cd.AppName == appName {
+				return true
+			}
+		}
+		return false
+	})
+	if !ok {
+		t.Errorf("unleashtest: no registration received for %q", appName)
+	}
+	return ok
+}
+
+// AssertCounts checks that the metrics received report the feature toggle as
+// enabled and disabled the given number of times, waiting up to a second for the
+// metrics to arrive.
+func (s *Server) AssertCounts(t testing.TB, feature string, yes, no int32) bool {
+	t.Helper()
+	ok := eventually(func() bool {
+		y, n := s.Counts(feature)
+		return y == yes && n == no
+	})
+	if !ok {
+		y, n := s.Counts(feature)
+		t.Errorf("unleashtest: expected %q to be counted %d yes and %d no, got %d yes and %d no", feature, yes, no, y, n)
+	}
+	return ok
+}
+
+func eventually(condition func() bool) bool {
+	deadline := time.Now().Add(assertTimeout)
+	for {
+		if condition() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// NewClient creates a client connected to the server and waits until it is ready.
+// The client refreshes the toggles and sends metrics every 10ms, keeps its backup
+// in a temporary directory and is closed when the test completes. The options
+// are applied after these defaults and can override them.
+func (s *Server) NewClient(t testing.TB, options ...unleash.ConfigOption) *unleash.Client {
+	t.Helper()
+	defaults := []unleash.ConfigOption{
+		unleash.WithUrl(s.URL),
+		unleash.WithAppName("unleashtest"),
+		unleash.WithInstanceId("unleashtest"),
+		unleash.WithListener(&unleash.NoopListener{}),
+		unleash.WithRefreshInterval(10 * time.Millisecond),
+		unleash.WithMetricsInterval(10 * time.Millisecond),
+		unleash.WithBackupPath(t.TempDir()),
+	}
+
+	client, err := unleash.NewClient(append(defaults, options...)...)
+	if err != nil {
+		t.Fatalf("unleashtest: creating client: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	ready := make(chan struct{})
+	go func() {
+		client.WaitForReady()
+		close(ready)
+	}()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("unleashtest: client did not become ready")
+	}
+	return client
+}
+
+func (s *Server) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	endpoint := Endpoint(req.URL.Path)
+
+	s.mu.Lock()
+	s.requests[endpoint]++
+	latency := s.latency
+	status, failing := s.failures[endpoint]
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-req.Context().Done():
+			return
+		}
+	}
+	if failing {
+		rw.WriteHeader(status)
+		return
+	}
+
+	switch req.Method + " " + req.URL.Path {
+	case "GET " + string(EndpointFeatures):
+		s.serveFeatures(rw, req)
+	case "POST " + string(EndpointRegister):
+		var cd unleash.ClientData
+		if err := json.NewDecoder(req.Body).Decode(&cd); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.registrations = append(s.registrations, cd)
+		s.mu.Unlock()
+		rw.WriteHeader(http.StatusAccepted)
+	case "POST " + string(EndpointMetrics):
+		var md unleash.MetricsData
+		if err := json.NewDecoder(req.Body).Decode(&md); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.metrics = append(s.metrics, md)
+		s.mu.Unlock()
+		rw.WriteHeader(http.StatusAccepted)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *Server) serveFeatures(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	etag := fmt.Sprintf(`"%d"`, s.version)
+	resp := api.FeatureResponse{
+		Response: api.Response{Version: 2},
+		Features: make([]api.Feature, 0, len(s.features)),
+		Segments: s.segments,
+	}
+	query := req.URL.Query()
+	for _, f := range s.features {
+		if matchesQuery(f, query) {
+			resp.Features = append(resp.Features, f)
+		}
+	}
+	s.mu.Unlock()
+
+	if req.Header.Get("If-None-Match") == etag {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	sort.Slice(resp.Features, func(i, j int) bool {
+		return resp.Features[i].Name < resp.Features[j].Name
+	})
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("ETag", etag)
+	json.NewEncoder(rw).Encode(resp)
+}
+
+// matchesQuery applies the filters of the features endpoint: the feature toggle
+// must belong to one of the projects and carry one of the tags, if any are given,
+// and its name must start with the prefix. A feature toggle without a project
+// belongs to the default project.
+func matchesQuery(f api.Feature, query url.Values) bool {
+	if projects := query["project"]; len(projects) > 0 {
+		project := f.Project
+		if project == "" {
+			project = "default"
+		}
+		if !contains(projects, project) {
+			return false
+		}
+	}
+	if !strings.HasPrefix(f.Name, query.Get("namePrefix")) {
+		return false
+	}
+	if tags := query["tag"]; len(tags) > 0 {
+		for _, tag := range f.Tags {
+			if contains(tags, tag.String()) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
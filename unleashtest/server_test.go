@@ -0,0 +1,121 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4"
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Enable("on")
+	srv.Disable("off")
+	srv.SetVariants("colors", api.VariantInternal{Variant: api.Variant{Name: "blue"}, Weight: 1000})
+	srv.SetSegments(api.Segment{Id: 1, Constraints: []api.Constraint{
+		{ContextName: "plan", Operator: api.OperatorIn, Values: []string{"pro"}},
+	}})
+	srv.SetFeature(api.Feature{
+		Name:       "pro-only",
+		Enabled:    true,
+		Strategies: []api.Strategy{{Id: 1, Name: "default", Segments: []int{1}}},
+	})
+
+	client := srv.NewClient(t, unleash.WithAppName("server-test"))
+
+	assert.True(client.IsEnabled("on"))
+	assert.False(client.IsEnabled("off"))
+	assert.Equal("blue", client.GetVariant("colors").Name)
+	assert.True(client.IsEnabled("pro-only", unleash.WithContext(context.Context{
+		Properties: map[string]string{"plan": "pro"},
+	})))
+
+	srv.AssertRegistered(t, "server-test")
+	srv.AssertCounts(t, "on", 1, 0)
+	srv.AssertCounts(t, "off", 0, 1)
+
+	srv.Disable("on")
+	assert.True(eventually(func() bool {
+		return !client.IsEnabled("on")
+	}))
+}
+
+func TestServer_Failures(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := NewServer()
+	defer srv.Close()
+	srv.Fail(EndpointFeatures, http.StatusInternalServerError)
+
+	errors := make(chan error, 10)
+	client, err := unleash.NewClient(
+		unleash.WithUrl(srv.URL),
+		unleash.WithAppName("server-test"),
+		unleash.WithDisableMetrics(true),
+		unleash.WithRefreshInterval(10*time.Millisecond),
+		unleash.WithBackupPath(t.TempDir()),
+		unleash.WithListener(&errorListener{errors}),
+	)
+	assert.NoError(err)
+	defer client.Close()
+
+	assert.Error(<-errors)
+	assert.True(srv.Requests(EndpointFeatures) >= 1)
+
+	srv.Recover(EndpointFeatures)
+	srv.Enable("on")
+	assert.True(eventually(func() bool {
+		return client.IsEnabled("on")
+	}))
+}
+
+type errorListener struct {
+	errors chan error
+}
+
+func (l *errorListener) OnError(err error) {
+	select {
+	case l.errors <- err:
+	default:
+	}
+}
+
+func (l *errorListener) OnWarning(error) {}
+
+func TestServer_Filters(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetFeature(api.Feature{Name: "checkout.new", Enabled: true, Project: "shop", Tags: []api.Tag{{Type: "team", Value: "payments"}}})
+	srv.SetFeature(api.Feature{Name: "checkout.old", Enabled: true, Project: "shop"})
+	srv.SetFeature(api.Feature{Name: "search", Enabled: true, Tags: []api.Tag{{Type: "team", Value: "payments"}}})
+
+	names := func(client *unleash.Client) []string {
+		names := []string{}
+		for _, f := range client.ListFeatures() {
+			names = append(names, f.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	assert.Equal([]string{"checkout.new", "checkout.old"}, names(srv.NewClient(t, unleash.WithProjects("shop"))))
+	assert.Equal([]string{"search"}, names(srv.NewClient(t, unleash.WithProjects("default"))))
+	assert.Equal([]string{"checkout.new", "checkout.old"}, names(srv.NewClient(t, unleash.WithNamePrefix("checkout."))))
+	assert.Equal([]string{"checkout.new", "search"}, names(srv.NewClient(t, unleash.WithTags("team:payments"))))
+	assert.Equal([]string{"checkout.new"}, names(srv.NewClient(t,
+		unleash.WithProjects("shop"),
+		unleash.WithTags("team:payments", "team:search"),
+	)))
+}
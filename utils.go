@@ -2,10 +2,11 @@ package unleash
 
 import (
 	"fmt"
+	"log/slog"
 	"math/rand"
+	"net/url"
 	"os"
 	"os/user"
-	"reflect"
 	"sync"
 	"time"
 )
@@ -30,9 +31,19 @@ func generateInstanceId() string {
 	return prefix
 }
 
-func getFetchURLPath(projectName string) string {
-	if projectName != "" {
-		return fmt.Sprintf("./client/features?project=%s", projectName)
+func getFetchURLPath(projects []string, namePrefix string, tags []string) string {
+	query := url.Values{}
+	for _, project := range projects {
+		query.Add("project", project)
+	}
+	if namePrefix != "" {
+		query.Set("namePrefix", namePrefix)
+	}
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+	if len(query) > 0 {
+		return "./client/features?" + query.Encode()
 	}
 	return "./client/features"
 }
@@ -49,32 +60,14 @@ func contains(arr []string, str string) bool {
 // WarnOnce is a type for handling warnings that should only be displayed once.
 type WarnOnce struct {
 	once sync.Once
+
+	// Logger receives the warning. If nil, the warning is discarded.
+	Logger *slog.Logger
 }
 
-// Warn logs the warning message once.
-func (wo *WarnOnce) Warn(message string) {
+// Warn logs the warning message once, with the given attributes.
+func (wo *WarnOnce) Warn(message string, args ...any) {
 	wo.once.Do(func() {
-		fmt.Println("Warning:", message)
+		loggerOrDiscard(wo.Logger).Warn(message, args...)
 	})
 }
-
-func every(slice interface{}, condition func(interface{}) bool) bool {
-	sliceValue := reflect.ValueOf(slice)
-
-	if sliceValue.Kind() != reflect.Slice {
-		fmt.Println("Input is not a slice returning false")
-		return false
-	}
-
-	if sliceValue.Len() == 0 {
-		return false
-	}
-
-	for i := 0; i < sliceValue.Len(); i++ {
-		element := sliceValue.Index(i).Interface()
-		if !condition(element) {
-			return false
-		}
-	}
-	return true
-}
@@ -0,0 +1,115 @@
+package unleash
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// WatchEvent is the outcome of a feature toggle watched with Client.Watch.
+type WatchEvent struct {
+	// Feature is the name of the feature toggle.
+	Feature string
+
+	// Enabled is the value IsEnabled returns for the watched context.
+	Enabled bool
+
+	// Variant is the variant GetVariant returns for the watched context.
+	Variant api.Variant
+}
+
+// watcher re-evaluates a feature toggle for a context when the repository changes.
+type watcher struct {
+	mu      sync.Mutex
+	ch      chan WatchEvent
+	last    WatchEvent
+	started bool
+	stopped bool
+}
+
+// emit evaluates the outcome and delivers it if it differs from the previous one.
+// Evaluating with the lock held keeps the outcomes in the order of the
+// repository updates. The channel only holds the latest outcome, so a slow reader
+// skips the intermediate ones.
+func (w *watcher) emit(evaluate func() WatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return
+	}
+	e := evaluate()
+	if w.started && e == w.last {
+		return
+	}
+	w.started = true
+	w.last = e
+	select {
+	case <-w.ch:
+	default:
+	}
+	w.ch <- e
+}
+
+func (w *watcher) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.stopped {
+		w.stopped = true
+		close(w.ch)
+	}
+}
+
+// Watch evaluates the specified feature for the given context and delivers the
+// outcome on the returned channel, then evaluates it again whenever the feature
+// toggles are fetched or become stale, and delivers the outcome only when the
+// enabled state or the variant differs. The channel holds the latest outcome
+// only: a reader that falls behind receives the most recent one.
+//
+// The values of the context providers are the ones at the time of each
+// evaluation: a provider refreshing its value does not trigger an evaluation by
+// itself, so the change is only delivered after the next fetch.
+//
+// The evaluations are not counted in the metrics. The returned function stops
+// watching and closes the channel; it must be called to release the watch.
+func (uc *Client) Watch(feature string, ctx context.Context) (<-chan WatchEvent, func()) {
+	w := &watcher{ch: make(chan WatchEvent, 1)}
+	evaluate := func() WatchEvent {
+		return uc.watchValue(feature, ctx)
+	}
+	unsubscribe := uc.Subscribe(func(e Event) {
+		var staleErr *StaleDataError
+		if we, ok := e.(WarningEvent); ok && !errors.As(we.Err, &staleErr) {
+			return
+		}
+		w.emit(evaluate)
+	}, EventFetched, EventWarning)
+	w.emit(evaluate)
+
+	var once sync.Once
+	return w.ch, func() {
+		once.Do(func() {
+			unsubscribe()
+			w.stop()
+		})
+	}
+}
+
+// watchValue evaluates the feature like IsEnabled and GetVariant do, without
+// counting the evaluation in the metrics. As it mostly runs from an event
+// handler, its errors are dropped rather than waiting for room in a full queue.
+func (uc *Client) watchValue(feature string, ctx context.Context) WatchEvent {
+	c := uc.context(&ctx)
+	result, _ := uc.evaluate(feature, featureOption{fromHandler: true}, c, true)
+
+	enabled := result.Enabled
+	if result.Feature == nil {
+		enabled = uc.fallback(feature, featureOption{}, c)
+	}
+	return WatchEvent{
+		Feature: feature,
+		Enabled: enabled,
+		Variant: *uc.variant(feature, variantOption{}, c, result),
+	}
+}
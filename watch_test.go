@@ -0,0 +1,183 @@
+package unleash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4/api"
+	"github.com/Unleash/unleash-client-go/v4/clock"
+	"github.com/Unleash/unleash-client-go/v4/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Watch(t *testing.T) {
+	assert := assert.New(t)
+
+	userStrategy := func(users string) []api.Strategy {
+		return []api.Strategy{{Name: "userWithId", Parameters: api.ParameterMap{"userIds": users}}}
+	}
+	payloads := []api.FeatureResponse{
+		{Features: []api.Feature{{Name: "feature", Enabled: false, Strategies: userStrategy("1")}}},
+		{Features: []api.Feature{{Name: "feature", Enabled: true, Strategies: userStrategy("1")}}},
+		// Changes the toggle without changing the outcome for user 1.
+		{Features: []api.Feature{{Name: "feature", Enabled: true, Strategies: userStrategy("1,2")}}},
+		{Features: []api.Feature{{Name: "feature", Enabled: true, Strategies: userStrategy("2")}}},
+	}
+	var stage int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/client/features" {
+			return
+		}
+		writeJSON(rw, payloads[atomic.LoadInt32(&stage)])
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithRefreshInterval(time.Millisecond),
+		WithBackupPath(t.TempDir()),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	events, cancel := client.Watch("feature", context.Context{UserId: "1"})
+
+	// Subscribed after the watch, so the handler sees an update after the watch
+	// has evaluated it.
+	updates := make(chan Event, 100)
+	client.Subscribe(func(e Event) { updates <- e }, EventUpdated)
+	e := <-events
+	assert.Equal("feature", e.Feature)
+	assert.False(e.Enabled)
+	assert.False(e.Variant.FeatureEnabled)
+
+	atomic.StoreInt32(&stage, 1)
+	e = <-events
+	assert.True(e.Enabled)
+	assert.Equal("disabled", e.Variant.Name)
+	assert.True(e.Variant.FeatureEnabled)
+
+	<-updates
+
+	atomic.StoreInt32(&stage, 2)
+	<-updates
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %+v", e)
+	default:
+	}
+
+	atomic.StoreInt32(&stage, 3)
+	e = <-events
+	assert.False(e.Enabled)
+
+	cancel()
+	cancel()
+	_, open := <-events
+	assert.False(open, "the channel is closed when the watch is cancelled")
+
+	assert.NoError(client.Close())
+}
+
+func TestClient_WatchWithOverflowBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	// Each evaluation of the enabled toggle reports two constraint errors, more
+	// than the queue holds.
+	dated := []api.Constraint{{ContextName: "currentTime", Operator: api.OperatorDateAfter, Value: "2022-01-01T00:00:00Z"}}
+	strategies := []api.Strategy{
+		{Id: 1, Name: "default", Constraints: dated},
+		{Id: 2, Name: "default", Constraints: dated},
+		{Id: 3, Name: "default"},
+	}
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/client/features" {
+			return
+		}
+		enabled := atomic.AddInt32(&fetches, 1)%2 == 0
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{
+			{Name: "feature", Enabled: enabled, Strategies: strategies},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithRefreshInterval(time.Millisecond),
+		WithBackupPath(t.TempDir()),
+		WithEventBufferSize(1),
+		WithEventOverflowPolicy(OverflowBlock),
+		WithErrorInterval(0),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	events, cancel := client.Watch("feature", context.Context{CurrentTime: "not-a-date"})
+	defer cancel()
+	for i := 0; i < 6; i++ {
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the watch blocked the delivery of the events")
+		}
+	}
+	assert.NoError(client.Close())
+}
+
+func TestClient_WatchStaleTransitions(t *testing.T) {
+	assert := assert.New(t)
+
+	var failing int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			// Dropping the connection fails the fetch without a backoff, so the
+			// next tick fetches again.
+			conn, _, _ := rw.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		writeJSON(rw, api.FeatureResponse{Features: []api.Feature{
+			{Name: "feature", Enabled: true, Strategies: []api.Strategy{{Name: "default"}}},
+		}})
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	client, err := NewClient(
+		WithUrl(srv.URL),
+		WithAppName(mockAppName),
+		WithInstanceId(mockInstanceId),
+		WithDisableMetrics(true),
+		WithClock(fake),
+		WithRefreshInterval(time.Minute),
+		WithBackupPath(t.TempDir()),
+		WithMaxDataAge(time.Hour),
+		WithStalePolicy(StaleUseFallbacks),
+	)
+	assert.NoError(err)
+	client.WaitForReady()
+
+	events, cancel := client.Watch("feature", context.Context{})
+	defer cancel()
+	assert.True((<-events).Enabled)
+
+	atomic.StoreInt32(&failing, 1)
+	fake.Advance(time.Hour + time.Minute)
+	assert.False((<-events).Enabled, "the fallback is used once the data is stale")
+
+	atomic.StoreInt32(&failing, 0)
+	fake.Advance(time.Minute)
+	assert.True((<-events).Enabled, "the feature toggle is served again once fetched")
+
+	assert.NoError(client.Close())
+}